@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+)
+
+// runCleanup purges deleted users whose grace period has ended and removes expired data exports
+// and used two-factor challenges,
+// once every helpers.PurgeInterval. It runs until the process exits.
+func runCleanup(app internal.Application) {
+	ticker := time.NewTicker(helpers.PurgeInterval)
+	defer ticker.Stop()
+
+	schedulePurges(app)
+	for {
+		purgeDeletedUsers(app)
+		removeExpiredExports(app)
+		removeExpiredChallenges(app)
+		<-ticker.C
+	}
+}
+
+// schedulePurges gives the users deleted before purging existed the configured grace period, starting now.
+func schedulePurges(app internal.Application) {
+	scheduled, err := app.Repositories.Users.SchedulePurges(time.Now().UTC().Add(app.Config.DeletionGracePeriod))
+	if err != nil {
+		log.Printf("error scheduling purges of deleted users: %s\n", err)
+		return
+	}
+	if scheduled > 0 {
+		log.Printf("scheduled %d deleted users to be purged in %s\n", scheduled, app.Config.DeletionGracePeriod)
+	}
+}
+
+// purgeDeletedUsers purges deleted users whose grace period has ended, in batches of helpers.PurgeBatchSize.
+// Users that fail to be purged are logged and retried on the next run.
+func purgeDeletedUsers(app internal.Application) {
+	for {
+		users, err := app.Repositories.Users.GetDueForPurge(time.Now().UTC(), helpers.PurgeBatchSize)
+		if err != nil {
+			log.Printf("error retrieving users due for purge: %s\n", err)
+			return
+		}
+
+		failed := 0
+		for _, user := range users {
+			if err := purgeUser(app, user); err != nil {
+				log.Printf("error purging user %s: %s\n", user.ID, err)
+				failed++
+			}
+		}
+
+		// failed users would fill every following batch, so they wait for the next run
+		if len(users) < helpers.PurgeBatchSize || failed > 0 {
+			return
+		}
+	}
+}
+
+// purgeUser deletes the uploaded media of a user before removing the user from the database,
+// so that a user whose media cannot be deleted is kept around to try again rather than leaving files behind.
+func purgeUser(app internal.Application, user models.User) error {
+	media, err := app.Repositories.Users.GetMedia(user.ID)
+	if err != nil {
+		return err
+	}
+
+	if user.AvatarURL != "" {
+		if err := app.Repositories.File.DeleteAvatar(user.ID); err != nil {
+			return err
+		}
+	}
+	for _, memoID := range media.MemoIDs {
+		if err := app.Repositories.File.DeleteMemoMedia(memoID); err != nil {
+			return err
+		}
+	}
+	for _, commentID := range media.CommentIDs {
+		if err := app.Repositories.File.DeleteCommentMedia(commentID); err != nil {
+			return err
+		}
+	}
+
+	// the user may have logged in and cancelled the deletion in the meantime
+	if err := app.Repositories.Users.Purge(user.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return nil
+		default:
+			return err
+		}
+	}
+
+	return os.RemoveAll(helpers.DataExportUserDir(app.Config.ExportDir, user.ID))
+}
+
+// removeExpiredExports deletes the archives of data exports whose download links have expired, along with the
+// exports that never completed, in batches of helpers.PurgeBatchSize.
+func removeExpiredExports(app internal.Application) {
+	for {
+		exports, err := app.Repositories.DataExports.GetExpired(time.Now().UTC(), helpers.PurgeBatchSize)
+		if err != nil {
+			log.Printf("error retrieving expired data exports: %s\n", err)
+			return
+		}
+
+		failed := 0
+		for _, export := range exports {
+			if err := removeExport(app, export); err != nil {
+				log.Printf("error removing data export %s: %s\n", export.ID, err)
+				failed++
+			}
+		}
+
+		if len(exports) < helpers.PurgeBatchSize || failed > 0 {
+			return
+		}
+	}
+}
+
+func removeExport(app internal.Application, export models.DataExport) error {
+	if export.FilePath != "" {
+		if err := os.Remove(export.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return app.Repositories.DataExports.Delete(export.ID)
+}
+
+// removeExpiredChallenges forgets the used two-factor challenge tokens that have expired.
+func removeExpiredChallenges(app internal.Application) {
+	if err := app.Repositories.TwoFactor.DeleteExpiredChallenges(time.Now().UTC()); err != nil {
+		log.Printf("error removing expired two-factor challenges: %s\n", err)
+	}
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/response"
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+)
+
+type AdminHandler interface {
+	GetUsers(ctx *gin.Context)
+	SuspendUser(ctx *gin.Context)
+	RestoreUser(ctx *gin.Context)
+	AssignRole(ctx *gin.Context)
+	RemoveRole(ctx *gin.Context)
+	GetMemos(ctx *gin.Context)
+	SuspendMemo(ctx *gin.Context)
+	RestoreMemo(ctx *gin.Context)
+	GetLockouts(ctx *gin.Context)
+}
+
+type adminHandler struct {
+	app internal.Application
+}
+
+func NewAdminHandler(app internal.Application) AdminHandler {
+	return adminHandler{app: app}
+}
+
+// GetUsers retrieves a list of users, including suspended and deleted users.
+// Only suspended users are listed when the suspended query parameter is true.
+func (ah adminHandler) GetUsers(ctx *gin.Context) {
+	page, pageSize, suspendedOnly, ok := moderationListParams(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := ah.app.Repositories.Moderation.GetUsers(suspendedOnly, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.MultipleAdminUserResponseFromModel(users),
+	)
+}
+
+// SuspendUser suspends a user, ending all of their sessions.
+// Suspended users cannot log in and their tokens stop working.
+func (ah adminHandler) SuspendUser(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	userID := ctx.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		return
+	}
+	if userID == user.ID {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("you cannot suspend yourself"))
+		return
+	}
+
+	suspendedUser, err := ah.app.Repositories.Moderation.SetUserSuspended(userID, true)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	if err := revokeAllSessions(ah.app, suspendedUser.ID); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.AdminUserResponseFromModel(suspendedUser),
+	)
+}
+
+// RestoreUser lifts the suspension of a user.
+func (ah adminHandler) RestoreUser(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		return
+	}
+
+	restoredUser, err := ah.app.Repositories.Moderation.SetUserSuspended(userID, false)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.AdminUserResponseFromModel(restoredUser),
+	)
+}
+
+// AssignRole grants a role to a user.
+func (ah adminHandler) AssignRole(ctx *gin.Context) {
+	// validate request
+	requestBody := struct {
+		Role string `json:"role" validate:"required"`
+	}{}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	userID := ctx.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		return
+	}
+	if _, err := ah.app.Repositories.Users.GetById(userID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	if err := ah.app.Repositories.Roles.Assign(userID, requestBody.Role); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("role not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Role was successfully assigned",
+		},
+	)
+}
+
+// RemoveRole takes a role away from a user.
+// Admins cannot remove their own admin role, so that at least one admin always remains.
+func (ah adminHandler) RemoveRole(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	userID := ctx.Param("id")
+	roleName := ctx.Param("role")
+	if _, err := uuid.Parse(userID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		return
+	}
+	if userID == user.ID && roleName == helpers.RoleAdmin {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("you cannot remove your own admin role"))
+		return
+	}
+
+	if err := ah.app.Repositories.Roles.Remove(userID, roleName); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user does not hold this role"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Role was successfully removed",
+		},
+	)
+}
+
+// GetMemos retrieves a list of memos, including suspended and deleted memos.
+// Only suspended memos are listed when the suspended query parameter is true.
+func (ah adminHandler) GetMemos(ctx *gin.Context) {
+	page, pageSize, suspendedOnly, ok := moderationListParams(ctx)
+	if !ok {
+		return
+	}
+
+	memos, err := ah.app.Repositories.Moderation.GetMemos(suspendedOnly, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.MultipleAdminMemoResponseFromModel(memos),
+	)
+}
+
+// SuspendMemo hides a memo from everyone but moderators.
+func (ah adminHandler) SuspendMemo(ctx *gin.Context) {
+	ah.setMemoSuspended(ctx, true)
+}
+
+// RestoreMemo makes a suspended memo visible again.
+func (ah adminHandler) RestoreMemo(ctx *gin.Context) {
+	ah.setMemoSuspended(ctx, false)
+}
+
+func (ah adminHandler) setMemoSuspended(ctx *gin.Context, suspended bool) {
+	memoID := ctx.Param("id")
+	if _, err := uuid.Parse(memoID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("memo not found"))
+		return
+	}
+
+	memo, err := ah.app.Repositories.Moderation.SetMemoSuspended(memoID, suspended)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("memo not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.AdminMemoResponseFromModel(memo),
+	)
+}
+
+// GetLockouts retrieves a list of the accounts and IP addresses locked out after failed logins, most recent first.
+func (ah adminHandler) GetLockouts(ctx *gin.Context) {
+	page, pageSize, _, ok := moderationListParams(ctx)
+	if !ok {
+		return
+	}
+
+	lockouts, err := ah.app.Repositories.LoginThrottle.GetLockouts(page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.MultipleLockoutResponseFromModel(lockouts),
+	)
+}
+
+// moderationListParams reads the page, pageSize and suspended query parameters.
+// An error response is written when false is returned.
+func moderationListParams(ctx *gin.Context) (page int, pageSize int, suspendedOnly bool, ok bool) {
+	pageStr := ctx.DefaultQuery("page", helpers.DefaultPage)
+	pageSizeStr := ctx.DefaultQuery("pageSize", helpers.DefaultPageSize)
+	suspendedStr := ctx.DefaultQuery("suspended", "false")
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("invalid value for page parameter"))
+		return 0, 0, false, false
+	}
+	pageSize, err = strconv.Atoi(pageSizeStr)
+	if err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("invalid value for pageSize parameter"))
+		return 0, 0, false, false
+	}
+	suspendedOnly, err = strconv.ParseBool(suspendedStr)
+	if err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("invalid value for suspended parameter"))
+		return 0, 0, false, false
+	}
+
+	return page, pageSize, suspendedOnly, true
+}
@@ -2,16 +2,23 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/request"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/response"
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
 	"github.com/akinolaemmanuel49/memo-api/domain/repository"
 )
 
@@ -19,6 +26,20 @@ type AuthHandler interface {
 	SignUp(ctx *gin.Context)
 	Token(ctx *gin.Context)
 	RefreshToken(ctx *gin.Context)
+	VerifyEmail(ctx *gin.Context)
+	ResendVerificationEmail(ctx *gin.Context)
+	ForgotPassword(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	EnrollTwoFactor(ctx *gin.Context)
+	ConfirmTwoFactor(ctx *gin.Context)
+	DisableTwoFactor(ctx *gin.Context)
+	RegenerateRecoveryCodes(ctx *gin.Context)
+	VerifyTwoFactor(ctx *gin.Context)
+	Logout(ctx *gin.Context)
+	GetSessions(ctx *gin.Context)
+	RevokeSession(ctx *gin.Context)
+	RevokeAllSessions(ctx *gin.Context)
+	JWKS(ctx *gin.Context)
 }
 
 type authHandler struct {
@@ -58,7 +79,7 @@ func (a authHandler) SignUp(ctx *gin.Context) {
 
 	// convert request to user model and hash password
 	user := requestBody.ToModel()
-	if err := helpers.HashPassword(&user.Password); err != nil {
+	if err := a.app.Passwords.HashPassword(&user.Password); err != nil {
 		helpers.HandleInternalServerError(ctx, err)
 		return
 	}
@@ -76,8 +97,11 @@ func (a authHandler) SignUp(ctx *gin.Context) {
 		return
 	}
 
+	// ask the user to verify their email address
+	go sendVerificationEmail(a.app, newUser)
+
 	// generate access and refresh tokens for user
-	accessToken, refreshToken, err := helpers.GenerateTokens(a.app.Config.JWTSecret, newUser.ID)
+	tokens, err := a.issueTokens(ctx, newUser.ID, "")
 	if err != nil {
 		helpers.HandleInternalServerError(ctx, err)
 		return
@@ -87,12 +111,8 @@ func (a authHandler) SignUp(ctx *gin.Context) {
 	ctx.JSON(
 		http.StatusCreated,
 		response.AuthResponse{
-			Tokens: response.Tokens{
-				AccessToken:  accessToken,
-				RefreshToken: refreshToken,
-				ExpiresAt:    time.Now().Add(helpers.AccessTokenDuration).Unix(),
-			},
-			User: response.UserResponseFromModel(newUser),
+			Tokens: tokens,
+			User:   response.UserResponseFromModel(newUser),
 		},
 	)
 
@@ -120,15 +140,13 @@ func (a authHandler) SignUp(ctx *gin.Context) {
 // Token authenticates a single user.
 func (a authHandler) Token(ctx *gin.Context) {
 	// validate request structure and contents
-	requestBody := request.User{}
+	requestBody := request.Login{}
 	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
 		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	err := requestBody.ValidateRequired(
-		request.UserFieldEmail,
-		request.UserFieldPassword)
+	err := requestBody.ValidateRequired()
 	if err != nil {
 		helpers.HandleValidationError(ctx, err)
 		return
@@ -141,31 +159,105 @@ func (a authHandler) Token(ctx *gin.Context) {
 	}
 
 	// fetch user data
-	user, err := a.app.Repositories.Users.GetByEmail(*requestBody.Email)
+	identifier := requestBody.LoginIdentifier()
+	user, err := a.getUserByIdentifier(identifier)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	found := err == nil
+
+	// failed logins count against the user whichever identifier was used
+	account := strings.ToLower(identifier)
+	if found {
+		account = user.ID
+	}
+
+	// refuse locked out accounts and IP addresses before spending time on the password check
+	lockedUntil, err := a.loginLockedUntil(ctx, account)
 	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrRecordNotFound):
-			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if !lockedUntil.IsZero() {
+		helpers.HandleLockedOut(ctx, lockedUntil)
+		return
+	}
 
-		default:
+	// unknown accounts take as long to reject as wrong passwords
+	if !found {
+		a.app.Passwords.VerifyNoPassword(*requestBody.Password)
+		if err := a.recordLoginFailure(ctx, account); err != nil {
 			helpers.HandleInternalServerError(ctx, err)
+			return
 		}
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
 		return
 	}
 
 	// verify password
-	valid, err := helpers.VerifyPassword(user.Password, *requestBody.Password)
+	valid, rehash, err := a.app.Passwords.VerifyPassword(user.Password, *requestBody.Password)
 	if err != nil {
 		helpers.HandleInternalServerError(ctx, err)
 		return
 	}
 	if !valid {
+		if err := a.recordLoginFailure(ctx, account); err != nil {
+			helpers.HandleInternalServerError(ctx, err)
+			return
+		}
 		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
 		return
 	}
 
+	// suspension is only revealed to users who know the password
+	if user.Suspended {
+		helpers.HandleErrorResponse(ctx, http.StatusForbidden, errors.New("account has been suspended"))
+		return
+	}
+
+	// upgrade hashes made with outdated parameters while the plaintext password is at hand
+	if rehash {
+		a.rehashPassword(user, *requestBody.Password)
+	}
+
+	// users with two-factor authentication enabled get a challenge to complete instead of tokens
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if twoFactor.Enabled {
+		challengeToken, expiresAt, err := helpers.GenerateTwoFactorChallengeToken(a.app.Keys, user.ID)
+		if err != nil {
+			helpers.HandleInternalServerError(ctx, err)
+			return
+		}
+
+		ctx.JSON(
+			http.StatusAccepted,
+			response.TwoFactorChallenge{
+				TwoFactorRequired: true,
+				ChallengeToken:    challengeToken,
+				ExpiresAt:         expiresAt.Unix(),
+			},
+		)
+		return
+	}
+
+	// logging in during the grace period cancels a pending deletion
+	if !a.cancelPendingDeletion(ctx, user) {
+		return
+	}
+
+	// a completed login clears the failed logins of the account
+	if err := a.app.Repositories.LoginThrottle.ResetFailures(helpers.LoginScopeAccount, account); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
 	// generate access and refresh tokens for user
-	accessToken, refreshToken, err := helpers.GenerateTokens(a.app.Config.JWTSecret, user.ID)
+	tokens, err := a.issueTokens(ctx, user.ID, "")
 	if err != nil {
 		helpers.HandleInternalServerError(ctx, err)
 		return
@@ -175,12 +267,8 @@ func (a authHandler) Token(ctx *gin.Context) {
 	ctx.JSON(
 		http.StatusOK,
 		response.AuthResponse{
-			Tokens: response.Tokens{
-				AccessToken:  accessToken,
-				RefreshToken: refreshToken,
-				ExpiresAt:    time.Now().Add(helpers.AccessTokenDuration).Unix(),
-			},
-			User: response.UserResponseFromModel(user),
+			Tokens: tokens,
+			User:   response.UserResponseFromModel(user),
 		},
 	)
 
@@ -224,28 +312,61 @@ func (a authHandler) RefreshToken(ctx *gin.Context) {
 	}
 
 	// validate refresh token
-	claims, err := helpers.ValidateToken(a.app.Config.JWTSecret, requestBody.RefreshToken)
+	invalidTokenErr := errors.New("invalid or expired refresh token")
+	claims, err := helpers.ValidateToken(a.app.Keys, requestBody.RefreshToken, helpers.TokenTypeRefresh)
+	if err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, invalidTokenErr)
+		return
+	}
+
+	// fetch the stored refresh token, only tokens issued by the server are accepted
+	storedToken, err := a.app.Repositories.Tokens.GetRefreshTokenByHash(helpers.HashToken(requestBody.RefreshToken))
 	if err != nil {
-		err := errors.New("invalid or expired refresh token")
-		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, err)
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, invalidTokenErr)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+	if storedToken.UserID != claims.Subject || time.Now().After(storedToken.ExpiresAt) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, invalidTokenErr)
 		return
 	}
 
-	// generate new access and refresh tokens
-	accessToken, refreshToken, err := helpers.GenerateTokens(a.app.Config.JWTSecret, claims.Subject)
+	// generate new access and refresh tokens in the same session
+	pair, err := helpers.GenerateTokens(a.app.Keys, storedToken.UserID, storedToken.FamilyID)
 	if err != nil {
 		helpers.HandleInternalServerError(ctx, err)
 		return
 	}
 
+	// replace the token with the new one, a token that was used before signals theft so its whole session is revoked
+	_, err = a.app.Repositories.Sessions.RotateRefreshToken(storedToken.ID, &models.RefreshToken{
+		UserID:    storedToken.UserID,
+		FamilyID:  storedToken.FamilyID,
+		TokenHash: helpers.HashToken(pair.RefreshToken),
+		ExpiresAt: pair.RefreshExpiresAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTokenReused):
+			if err := a.revokeSession(storedToken.UserID, storedToken.FamilyID); err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+				helpers.HandleInternalServerError(ctx, err)
+				return
+			}
+			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, invalidTokenErr)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
 	// return new tokens
 	ctx.JSON(
 		http.StatusOK,
-		response.Tokens{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-			ExpiresAt:    time.Now().Add(helpers.AccessTokenDuration).Unix(),
-		},
+		tokensResponse(pair),
 	)
 
 	/** sample response
@@ -256,3 +377,959 @@ func (a authHandler) RefreshToken(ctx *gin.Context) {
 	}
 	*/
 }
+
+// VerifyEmail activates the account a verification token was issued for.
+func (a authHandler) VerifyEmail(ctx *gin.Context) {
+	// validate request
+	requestBody := struct {
+		Token string `json:"token" validate:"required"`
+	}{}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	// validate verification token
+	invalidTokenErr := errors.New("invalid or expired verification token")
+	claims, err := helpers.ValidateToken(a.app.Keys, requestBody.Token, helpers.TokenTypeEmailVerification)
+	if err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, invalidTokenErr)
+		return
+	}
+
+	// fetch user data
+	user, err := a.app.Repositories.Users.GetById(claims.Subject)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, invalidTokenErr)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	// the token is only good for the address it was sent to, and only until that address is verified
+	if user.Email != claims.Email {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, invalidTokenErr)
+		return
+	}
+	if user.IsActivated {
+		helpers.HandleErrorResponse(ctx, http.StatusConflict, errors.New("email address is already verified"))
+		return
+	}
+
+	user.IsActivated = true
+	updatedUser, err := a.app.Repositories.Users.Update(user.ID, user)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrConcurrentUpdate):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Email address was successfully verified.",
+			"data":    response.UserResponseFromModel(updatedUser),
+		},
+	)
+}
+
+// ResendVerificationEmail sends a new verification link to an authenticated user.
+func (a authHandler) ResendVerificationEmail(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	if user.IsActivated {
+		helpers.HandleErrorResponse(ctx, http.StatusConflict, errors.New("email address is already verified"))
+		return
+	}
+
+	go sendVerificationEmail(a.app, user)
+
+	ctx.JSON(
+		http.StatusAccepted,
+		gin.H{
+			"message": "Verification email was sent.",
+		},
+	)
+}
+
+// ForgotPassword mails a password reset link to the owner of an email address.
+// The response is the same whether or not the address belongs to an account.
+func (a authHandler) ForgotPassword(ctx *gin.Context) {
+	// validate request
+	requestBody := struct {
+		Email string `json:"email" validate:"required,email"`
+	}{}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	// the lookup and delivery happen in the background so response times do not reveal whether the account exists
+	go sendPasswordResetEmail(a.app, requestBody.Email)
+
+	ctx.JSON(
+		http.StatusAccepted,
+		gin.H{
+			"message": "If an account with that email address exists, a password reset link has been sent to it.",
+		},
+	)
+}
+
+// ResetPassword sets a new password using a password reset token and signs the user out everywhere.
+func (a authHandler) ResetPassword(ctx *gin.Context) {
+	// validate request
+	requestBody := struct {
+		Token    string `json:"token" validate:"required"`
+		Password string `json:"password" validate:"required,min=6,excludes= "`
+	}{}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	// fetch the stored reset token
+	invalidTokenErr := errors.New("invalid or expired password reset token")
+	resetToken, err := a.app.Repositories.Tokens.GetPasswordResetTokenByHash(helpers.HashToken(requestBody.Token))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, invalidTokenErr)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+	if resetToken.UsedAt.Valid || time.Now().After(resetToken.ExpiresAt) {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, invalidTokenErr)
+		return
+	}
+
+	// fetch user data
+	user, err := a.app.Repositories.Users.GetById(resetToken.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, invalidTokenErr)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	// hash the new password, then save it while using up the token
+	passwordHash := requestBody.Password
+	if err := a.app.Passwords.HashPassword(&passwordHash); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if err := a.app.Repositories.Tokens.ResetPassword(resetToken.ID, user.ID, passwordHash); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTokenReused), errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, invalidTokenErr)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	// existing sessions may belong to whoever caused the reset
+	if err := revokeAllSessions(a.app, user.ID); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Password was successfully reset.",
+		},
+	)
+}
+
+// EnrollTwoFactor starts TOTP enrollment for an authenticated user, returning the secret to add to an authenticator app.
+// Two-factor authentication is only enabled once the enrollment is confirmed.
+func (a authHandler) EnrollTwoFactor(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	sealedSecret, err := a.app.Secrets.Seal(secret)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	if _, err := a.app.Repositories.TwoFactor.SetPendingSecret(user.ID, sealedSecret); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTwoFactorEnabled):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.TwoFactorEnrollment{
+			Secret:     secret,
+			OTPAuthURI: helpers.TOTPURI(secret, user.Email),
+		},
+	)
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user proves their authenticator produces valid codes.
+// The one-time recovery codes are returned, this is the only time they are shown.
+func (a authHandler) ConfirmTwoFactor(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	code, ok := bindTwoFactorCode(ctx)
+	if !ok {
+		return
+	}
+
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("two-factor enrollment has not been started"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+	if twoFactor.Enabled {
+		helpers.HandleErrorResponse(ctx, http.StatusConflict, repository.ErrTwoFactorEnabled)
+		return
+	}
+
+	valid, err := a.checkSecondFactor(twoFactor, code)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if !valid {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid two-factor code"))
+		return
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := newRecoveryCodes()
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	if err := a.app.Repositories.TwoFactor.Enable(user.ID, recoveryCodeHashes); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, repository.ErrTwoFactorEnabled)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message":       "Two-factor authentication was successfully enabled.",
+			"recoveryCodes": recoveryCodes,
+		},
+	)
+}
+
+// DisableTwoFactor turns off two-factor authentication for an authenticated user.
+// A current TOTP code or a recovery code is required.
+func (a authHandler) DisableTwoFactor(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	twoFactor, ok := a.requireSecondFactor(ctx, user.ID)
+	if !ok {
+		return
+	}
+
+	if err := a.app.Repositories.TwoFactor.Disable(twoFactor.UserID); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Two-factor authentication was successfully disabled.",
+		},
+	)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of an authenticated user.
+// A current TOTP code or a recovery code is required.
+func (a authHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	twoFactor, ok := a.requireSecondFactor(ctx, user.ID)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := newRecoveryCodes()
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	if err := a.app.Repositories.TwoFactor.ReplaceRecoveryCodes(twoFactor.UserID, recoveryCodeHashes); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"recoveryCodes": recoveryCodes,
+		},
+	)
+}
+
+// VerifyTwoFactor completes a login started at Token, exchanging a challenge token and a
+// TOTP or recovery code for access and refresh tokens.
+func (a authHandler) VerifyTwoFactor(ctx *gin.Context) {
+	// validate request
+	requestBody := struct {
+		ChallengeToken string `json:"challengeToken" validate:"required"`
+		Code           string `json:"code" validate:"required"`
+	}{}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	// validate challenge token
+	claims, err := helpers.ValidateToken(a.app.Keys, requestBody.ChallengeToken, helpers.TokenTypeTwoFactor)
+	if err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid or expired challenge token"))
+		return
+	}
+
+	// fetch user data
+	user, err := a.app.Repositories.Users.GetById(claims.Subject)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	if user.Suspended {
+		helpers.HandleErrorResponse(ctx, http.StatusForbidden, errors.New("account has been suspended"))
+		return
+	}
+
+	// failed codes count against the account like failed passwords do
+	account := user.ID
+	lockedUntil, err := a.loginLockedUntil(ctx, account)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if !lockedUntil.IsZero() {
+		helpers.HandleLockedOut(ctx, lockedUntil)
+		return
+	}
+
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if !twoFactor.Enabled {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid or expired challenge token"))
+		return
+	}
+
+	valid, err := a.checkSecondFactor(twoFactor, requestBody.Code)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if !valid {
+		if err := a.recordLoginFailure(ctx, account); err != nil {
+			helpers.HandleInternalServerError(ctx, err)
+			return
+		}
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid two-factor code"))
+		return
+	}
+
+	// each challenge token completes a single login
+	err = a.app.Repositories.TwoFactor.UseChallenge(claims.Id, user.ID, time.Unix(claims.ExpiresAt, 0))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTokenReused):
+			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid or expired challenge token"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	// logging in during the grace period cancels a pending deletion
+	if !a.cancelPendingDeletion(ctx, user) {
+		return
+	}
+
+	// a completed login clears the failed logins of the account
+	if err := a.app.Repositories.LoginThrottle.ResetFailures(helpers.LoginScopeAccount, account); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	// generate access and refresh tokens for user
+	tokens, err := a.issueTokens(ctx, user.ID, "")
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	// return logged-in user with tokens
+	ctx.JSON(
+		http.StatusOK,
+		response.AuthResponse{
+			Tokens: tokens,
+			User:   response.UserResponseFromModel(user),
+		},
+	)
+}
+
+// getUserByIdentifier retrieves a user via their username or email address, ignoring case.
+// Identifiers that look like an email address are tried as one first, since usernames may contain an @ too.
+// repository.ErrRecordNotFound is returned if no user matches, including deleted users past their grace period.
+func (a authHandler) getUserByIdentifier(identifier string) (models.User, error) {
+	var user models.User
+	err := repository.ErrRecordNotFound
+	if strings.Contains(identifier, "@") {
+		user, err = a.app.Repositories.Users.GetByEmail(identifier)
+	}
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		user, err = a.app.Repositories.Users.GetByUsername(identifier)
+	}
+	// deleted users are returned along with repository.ErrRecordDeleted
+	if err != nil && !errors.Is(err, repository.ErrRecordDeleted) {
+		return models.User{}, err
+	}
+
+	// deleted users can only log in while their deletion can still be cancelled
+	if user.Deleted && (!user.PurgeAfter.Valid || !time.Now().Before(user.PurgeAfter.Time)) {
+		return models.User{}, repository.ErrRecordNotFound
+	}
+
+	return user, nil
+}
+
+// cancelPendingDeletion restores a deleted user who logs in during the grace period.
+// An error response is written when false is returned.
+func (a authHandler) cancelPendingDeletion(ctx *gin.Context, user models.User) bool {
+	if !user.Deleted {
+		return true
+	}
+
+	if err := a.app.Repositories.Users.CancelDeletion(user.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid credentials"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return false
+	}
+
+	return true
+}
+
+// rehashPassword stores a new hash of a password made with the current hashing parameters.
+// Failures are only logged since the login itself has succeeded.
+func (a authHandler) rehashPassword(user models.User, password string) {
+	if err := a.app.Passwords.HashPassword(&password); err != nil {
+		log.Printf("rehashing password of user %s: %s", user.ID, err)
+		return
+	}
+	if err := a.app.Repositories.Users.UpdatePasswordHash(user.ID, user.Password, password); err != nil && !errors.Is(err, repository.ErrConcurrentUpdate) {
+		log.Printf("rehashing password of user %s: %s", user.ID, err)
+	}
+}
+
+// loginLockedUntil returns the time the account or client IP address of a login is locked out until,
+// or the zero time if neither is locked out.
+func (a authHandler) loginLockedUntil(ctx *gin.Context, account string) (time.Time, error) {
+	subjects := []struct {
+		scope   string
+		subject string
+	}{
+		{helpers.LoginScopeAccount, account},
+		{helpers.LoginScopeIP, ctx.ClientIP()},
+	}
+
+	var lockedUntil time.Time
+	for _, s := range subjects {
+		failure, err := a.app.Repositories.LoginThrottle.GetFailure(s.scope, s.subject)
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		if failure.LockedUntil.Valid && failure.LockedUntil.Time.After(lockedUntil) {
+			lockedUntil = failure.LockedUntil.Time
+		}
+	}
+
+	if !lockedUntil.After(time.Now()) {
+		return time.Time{}, nil
+	}
+	return lockedUntil, nil
+}
+
+// recordLoginFailure counts a failed login against both the account and the client IP address,
+// locking either out once its failures reach the lockout threshold.
+func (a authHandler) recordLoginFailure(ctx *gin.Context, account string) error {
+	subjects := []struct {
+		scope     string
+		subject   string
+		threshold int
+	}{
+		{helpers.LoginScopeAccount, account, helpers.AccountLockoutThreshold},
+		{helpers.LoginScopeIP, ctx.ClientIP(), helpers.IPLockoutThreshold},
+	}
+
+	for _, s := range subjects {
+		failure, err := a.app.Repositories.LoginThrottle.RecordFailure(s.scope, s.subject, helpers.LoginFailureWindow)
+		if err != nil {
+			return err
+		}
+
+		duration := helpers.LockoutDuration(failure.Failures, s.threshold)
+		if duration == 0 {
+			continue
+		}
+
+		_, err = a.app.Repositories.LoginThrottle.Lock(&models.Lockout{
+			Scope:       s.scope,
+			Subject:     s.subject,
+			IPAddress:   ctx.ClientIP(),
+			Failures:    failure.Failures,
+			LockedUntil: time.Now().Add(duration),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requireSecondFactor reads a code from the request body and checks it against the enabled
+// two-factor enrollment of a user. An error response is written when false is returned.
+func (a authHandler) requireSecondFactor(ctx *gin.Context, userID string) (models.TwoFactor, bool) {
+	code, ok := bindTwoFactorCode(ctx)
+	if !ok {
+		return models.TwoFactor{}, false
+	}
+
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(userID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		helpers.HandleInternalServerError(ctx, err)
+		return models.TwoFactor{}, false
+	}
+	if !twoFactor.Enabled {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("two-factor authentication is not enabled"))
+		return models.TwoFactor{}, false
+	}
+
+	valid, err := a.checkSecondFactor(twoFactor, code)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return models.TwoFactor{}, false
+	}
+	if !valid {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid two-factor code"))
+		return models.TwoFactor{}, false
+	}
+
+	return twoFactor, true
+}
+
+// checkSecondFactor accepts a TOTP code that has not been used before, or an unused recovery code once
+// two-factor authentication is enabled. Accepted recovery codes are used up.
+func (a authHandler) checkSecondFactor(twoFactor models.TwoFactor, code string) (bool, error) {
+	secret, err := a.app.Secrets.Open(twoFactor.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	if step, ok := helpers.ValidateTOTP(secret, code, time.Now()); ok {
+		err := a.app.Repositories.TwoFactor.UseStep(twoFactor.UserID, step)
+		if errors.Is(err, repository.ErrTokenReused) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	if !twoFactor.Enabled {
+		return false, nil
+	}
+
+	err = a.app.Repositories.TwoFactor.ConsumeRecoveryCode(twoFactor.UserID, helpers.HashToken(helpers.NormalizeRecoveryCode(code)))
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// bindTwoFactorCode reads the code field of the request body. An error response is written when false is returned.
+func bindTwoFactorCode(ctx *gin.Context) (string, bool) {
+	requestBody := struct {
+		Code string `json:"code" validate:"required"`
+	}{}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return "", false
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return "", false
+	}
+
+	return requestBody.Code, true
+}
+
+// newRecoveryCodes generates a set of recovery codes along with the hashes to store for them.
+func newRecoveryCodes() ([]string, []string, error) {
+	recoveryCodes, err := helpers.GenerateRecoveryCodes(helpers.RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recoveryCodeHashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		recoveryCodeHashes = append(recoveryCodeHashes, helpers.HashToken(helpers.NormalizeRecoveryCode(code)))
+	}
+	return recoveryCodes, recoveryCodeHashes, nil
+}
+
+// Logout ends the session the request was authenticated with.
+func (a authHandler) Logout(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	if err := a.revokeSession(user.ID, helpers.ContextGetSessionID(ctx)); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Logout was successful.",
+		},
+	)
+}
+
+// GetSessions retrieves the active sessions of an authenticated user.
+func (a authHandler) GetSessions(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	sessions, err := a.app.Repositories.Sessions.GetActiveByUserID(user.ID)
+	if err != nil {
+		switch {
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	// return fetched sessions
+	ctx.JSON(
+		http.StatusOK,
+		response.MultipleSessionResponseFromModel(sessions, helpers.ContextGetSessionID(ctx)),
+	)
+}
+
+// RevokeSession ends one of the sessions of an authenticated user.
+func (a authHandler) RevokeSession(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	sessionID := ctx.Param("id")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		return
+	}
+
+	if err := a.revokeSession(user.ID, sessionID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Session was successfully revoked.",
+		},
+	)
+}
+
+// RevokeAllSessions ends every session of an authenticated user, signing them out everywhere.
+func (a authHandler) RevokeAllSessions(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	if err := revokeAllSessions(a.app, user.ID); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "All sessions were successfully revoked.",
+		},
+	)
+}
+
+// JWKS publishes the public keys tokens are verified with, so that other services can verify them.
+func (a authHandler) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(helpers.JWKSCacheDuration.Seconds())))
+	ctx.JSON(
+		http.StatusOK,
+		response.JWKS{Keys: a.app.Keys.JWKS()},
+	)
+}
+
+// revokeSession revokes a session and drops it from the session cache so that its access tokens stop working.
+// The session is dropped from the cache only once it is revoked, so that a concurrent request cannot cache it again.
+func (a authHandler) revokeSession(userID string, sessionID string) error {
+	err := a.app.Repositories.Sessions.Revoke(sessionID, userID)
+	a.app.ActiveSessions.Delete(sessionID)
+	return err
+}
+
+// revokeAllSessions revokes every session of a user and drops them from the session cache.
+func revokeAllSessions(app internal.Application, userID string) error {
+	sessionIDs, err := app.Repositories.Sessions.RevokeAll(userID)
+	if err != nil {
+		return err
+	}
+	for _, sessionID := range sessionIDs {
+		app.ActiveSessions.Delete(sessionID)
+	}
+	return nil
+}
+
+// issueTokens generates a new access and refresh token pair for a user and stores the refresh token.
+// The refresh token joins the given session, or a new session is started for the request when sessionID is empty.
+func (a authHandler) issueTokens(ctx *gin.Context, userID string, sessionID string) (response.Tokens, error) {
+	if sessionID == "" {
+		newSession, err := a.app.Repositories.Sessions.Create(&models.Session{
+			UserID:    userID,
+			Device:    deviceName(ctx),
+			IPAddress: ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+		})
+		if err != nil {
+			return response.Tokens{}, err
+		}
+		sessionID = newSession.ID
+	}
+
+	pair, err := helpers.GenerateTokens(a.app.Keys, userID, sessionID)
+	if err != nil {
+		return response.Tokens{}, err
+	}
+
+	_, err = a.app.Repositories.Tokens.CreateRefreshToken(&models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  sessionID,
+		TokenHash: helpers.HashToken(pair.RefreshToken),
+		ExpiresAt: pair.RefreshExpiresAt,
+	})
+	if err != nil {
+		return response.Tokens{}, err
+	}
+
+	return tokensResponse(pair), nil
+}
+
+// tokensResponse returns the response carrying a newly generated token pair.
+func tokensResponse(pair helpers.TokenPair) response.Tokens {
+	return response.Tokens{
+		AccessToken:      pair.AccessToken,
+		RefreshToken:     pair.RefreshToken,
+		ExpiresAt:        pair.AccessExpiresAt.Unix(),
+		RefreshExpiresAt: pair.RefreshExpiresAt.Unix(),
+	}
+}
+
+// deviceName returns the client supplied X-Device-Name header,
+// falling back to the platform named in the User-Agent header.
+func deviceName(ctx *gin.Context) string {
+	if name := ctx.GetHeader("X-Device-Name"); name != "" {
+		return name
+	}
+
+	userAgent := ctx.Request.UserAgent()
+	platforms := []struct {
+		marker string
+		name   string
+	}{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Macintosh", "macOS"},
+		{"Linux", "Linux"},
+	}
+	for _, platform := range platforms {
+		if strings.Contains(userAgent, platform.marker) {
+			return platform.name
+		}
+	}
+	return ""
+}
+
+// sendVerificationEmail mails a user a link to verify their email address.
+// It is meant to run in its own goroutine, so failures are logged rather than returned.
+func sendVerificationEmail(app internal.Application, user models.User) {
+	token, err := helpers.GenerateEmailVerificationToken(app.Keys, user.ID, user.Email)
+	if err != nil {
+		log.Printf("error generating verification token for user %s: %s", user.ID, err.Error())
+		return
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", strings.TrimSuffix(app.Config.AppURL, "/"), url.QueryEscape(token))
+	if err := app.Repositories.Mailer.Send(helpers.VerificationEmail(user, link)); err != nil {
+		log.Printf("error sending verification email to user %s: %s", user.ID, err.Error())
+	}
+}
+
+// sendPasswordResetEmail mails a password reset link to the user with a matching email address, if there is one.
+// It is meant to run in its own goroutine, so failures are logged rather than returned.
+func sendPasswordResetEmail(app internal.Application, email string) {
+	user, err := app.Repositories.Users.GetByEmail(email)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) && !errors.Is(err, repository.ErrRecordDeleted) {
+			log.Printf("error fetching user for password reset: %s", err.Error())
+		}
+		return
+	}
+	if user.Deleted {
+		return
+	}
+
+	token, err := helpers.GenerateOpaqueToken()
+	if err != nil {
+		log.Printf("error generating password reset token for user %s: %s", user.ID, err.Error())
+		return
+	}
+
+	_, err = app.Repositories.Tokens.CreatePasswordResetToken(&models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: time.Now().Add(helpers.PasswordResetTokenDuration),
+	})
+	if err != nil {
+		log.Printf("error storing password reset token for user %s: %s", user.ID, err.Error())
+		return
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", strings.TrimSuffix(app.Config.AppURL, "/"), url.QueryEscape(token))
+	if err := app.Repositories.Mailer.Send(helpers.PasswordResetEmail(user, link)); err != nil {
+		log.Printf("error sending password reset email to user %s: %s", user.ID, err.Error())
+	}
+}
@@ -0,0 +1,543 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/request"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/response"
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+)
+
+type ListHandler interface {
+	Create(ctx *gin.Context)
+	GetOwnLists(ctx *gin.Context)
+	GetFollowedLists(ctx *gin.Context)
+	GetUserLists(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	AddMember(ctx *gin.Context)
+	RemoveMember(ctx *gin.Context)
+	GetMembers(ctx *gin.Context)
+	Follow(ctx *gin.Context)
+	Unfollow(ctx *gin.Context)
+	GetFeed(ctx *gin.Context)
+}
+
+type listHandler struct {
+	app internal.Application
+}
+
+func NewListHandler(app internal.Application) ListHandler {
+	return listHandler{app: app}
+}
+
+// Create creates a new list owned by the authenticated user. Lists are private unless stated otherwise.
+func (lh listHandler) Create(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	// validate request
+	requestBody := request.List{}
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+	if err := requestBody.ValidateRequired(request.ListFieldName); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	list := requestBody.ToModel()
+	list.OwnerID = user.ID
+	if list.Name == "" {
+		helpers.HandleValidationError(ctx, errors.New("name must not be blank"))
+		return
+	}
+
+	newList, err := lh.app.Repositories.Lists.Create(&list)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateList):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusCreated,
+		gin.H{
+			"status":  "success",
+			"message": "List successfully created.",
+			"data":    response.ListResponseFromModel(newList),
+		},
+	)
+}
+
+// GetOwnLists retrieves the lists of the authenticated user, private lists included, most recently created first.
+func (lh listHandler) GetOwnLists(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	lists, err := lh.app.Repositories.Lists.GetByOwnerID(user.ID, true, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleListResponseFromModel(lists),
+	})
+}
+
+// GetFollowedLists retrieves the lists followed by the authenticated user, most recently followed first.
+func (lh listHandler) GetFollowedLists(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	lists, err := lh.app.Repositories.Lists.GetFollowedByUser(user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleListResponseFromModel(lists),
+	})
+}
+
+// GetUserLists retrieves the public lists of another user, most recently created first.
+func (lh listHandler) GetUserLists(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	owner, ok := findUser(lh.app, ctx, ctx.Param("userID"))
+	if !ok {
+		return
+	}
+	if !ensureNotBlocked(lh.app, ctx, user.ID, owner.ID) {
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	lists, err := lh.app.Repositories.Lists.GetByOwnerID(owner.ID, owner.ID == user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleListResponseFromModel(lists),
+	})
+}
+
+// Get retrieves a list. Private lists are only found by their owner.
+func (lh listHandler) Get(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.ListResponseFromModel(list),
+	})
+}
+
+// Update changes the name, description or privacy of a list owned by the authenticated user.
+// Making a list private removes its followers.
+func (lh listHandler) Update(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findOwnList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	// validate request
+	requestBody := request.List{}
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	changedList := requestBody.ApplyTo(list)
+	if changedList.Name == "" {
+		helpers.HandleValidationError(ctx, errors.New("name must not be blank"))
+		return
+	}
+
+	updatedList, err := lh.app.Repositories.Lists.Update(list.ID, changedList)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("list not found"))
+		case errors.Is(err, repository.ErrDuplicateList):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		case errors.Is(err, repository.ErrConcurrentUpdate):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "List successfully updated.",
+			"data":    response.ListResponseFromModel(updatedList),
+		},
+	)
+}
+
+// Delete removes a list owned by the authenticated user.
+func (lh listHandler) Delete(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findOwnList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := lh.app.Repositories.Lists.Delete(list.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("list not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "List successfully deleted.",
+		},
+	)
+}
+
+// AddMember adds a user to a list owned by the authenticated user, so that their memos show up in its feed.
+func (lh listHandler) AddMember(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findOwnList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	member, ok := findUser(lh.app, ctx, ctx.Param("userID"))
+	if !ok {
+		return
+	}
+	if !ensureNotBlocked(lh.app, ctx, user.ID, member.ID) {
+		return
+	}
+
+	if err := lh.app.Repositories.Lists.AddMember(list.ID, member.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateListMember):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully added to the list.",
+			"data": gin.H{
+				"listID":   list.ID,
+				"userID":   member.ID,
+				"username": member.Username,
+			},
+		},
+	)
+}
+
+// RemoveMember removes a user from a list owned by the authenticated user.
+func (lh listHandler) RemoveMember(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findOwnList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	memberID := ctx.Param("userID")
+	if _, err := uuid.Parse(memberID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user is not a member of this list"))
+		return
+	}
+
+	if err := lh.app.Repositories.Lists.RemoveMember(list.ID, memberID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user is not a member of this list"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully removed from the list.",
+		},
+	)
+}
+
+// GetMembers retrieves the members of a list, most recently added first.
+func (lh listHandler) GetMembers(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := lh.app.Repositories.Lists.GetMembers(list.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultiplePublicUserResponseFromModel(users),
+	})
+}
+
+// Follow adds a public list of another user to the lists followed by the authenticated user.
+func (lh listHandler) Follow(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	if list.OwnerID == user.ID {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("users cannot follow their own lists"))
+		return
+	}
+
+	if err := lh.app.Repositories.Lists.Follow(list.ID, user.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateListFollow):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "List successfully followed.",
+		},
+	)
+}
+
+// Unfollow removes a list from the lists followed by the authenticated user.
+func (lh listHandler) Unfollow(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	listID := ctx.Param("id")
+	if _, err := uuid.Parse(listID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("list not found"))
+		return
+	}
+
+	if err := lh.app.Repositories.Lists.Unfollow(listID, user.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("list is not followed"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "List successfully unfollowed.",
+		},
+	)
+}
+
+// GetFeed fetches the memos of the members of a list, newest first, as the authenticated user is allowed to see them.
+func (lh listHandler) GetFeed(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	list, ok := findList(lh.app, ctx, user.ID, ctx.Param("id"))
+	if !ok {
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	memos, err := lh.app.Repositories.Lists.GetFeed(list.ID, user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleMemoResponseFromModel(memos),
+	})
+}
+
+// findList retrieves a list for a user to see. Private lists of other users are reported as not found,
+// and lists of users blocking or blocked by the user are forbidden. An error response is written when false is returned.
+func findList(app internal.Application, ctx *gin.Context, userID, listID string) (models.List, bool) {
+	if _, err := uuid.Parse(listID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("list not found"))
+		return models.List{}, false
+	}
+
+	list, err := app.Repositories.Lists.Get(listID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("list not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return models.List{}, false
+	}
+	if list.Private && list.OwnerID != userID {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("list not found"))
+		return models.List{}, false
+	}
+	if !ensureNotBlocked(app, ctx, userID, list.OwnerID) {
+		return models.List{}, false
+	}
+
+	return list, true
+}
+
+// findOwnList retrieves a list for its owner to change. Public lists of other users are forbidden.
+// An error response is written when false is returned.
+func findOwnList(app internal.Application, ctx *gin.Context, userID, listID string) (models.List, bool) {
+	list, ok := findList(app, ctx, userID, listID)
+	if !ok {
+		return models.List{}, false
+	}
+	if list.OwnerID != userID {
+		helpers.HandleErrorResponse(ctx, http.StatusForbidden, errors.New("only the owner of a list can change it"))
+		return models.List{}, false
+	}
+
+	return list, true
+}
@@ -24,6 +24,9 @@ type MemoHandler interface {
 	CreateAudioMemo(ctx *gin.Context)
 	GetMemo(ctx *gin.Context)
 	DeleteMemo(ctx *gin.Context)
+	EditMemo(ctx *gin.Context)
+	GetMemoRevisions(ctx *gin.Context)
+	UpdateVisibility(ctx *gin.Context)
 	LikeMemo(ctx *gin.Context)
 	UnlikeMemo(ctx *gin.Context)
 	ShareMemo(ctx *gin.Context)
@@ -76,6 +79,16 @@ func (mh memoHandler) CreateTextMemo(ctx *gin.Context) {
 	textMemo := requestBody.ToModel()
 	textMemo.OwnerID = user.ID
 	textMemo.MemoType = "text"
+	audience, ok := parseAudience(ctx, textMemo.Audience)
+	if !ok {
+		return
+	}
+	visibility, ok := parseVisibility(ctx, textMemo.Visibility)
+	if !ok {
+		return
+	}
+	textMemo.Audience = audience
+	textMemo.Visibility = visibility
 
 	// attempt to save text memo in repository
 	newTextMemo, err := mh.app.Repositories.Memo.CreateMemo(user.ID, &textMemo)
@@ -106,11 +119,21 @@ func (mh memoHandler) CreateImageMemo(ctx *gin.Context) {
 
 	// Validate request data
 	caption := ctx.PostForm("caption")
+	audience, ok := parseAudience(ctx, ctx.PostForm("audience"))
+	if !ok {
+		return
+	}
+	visibility, ok := parseVisibility(ctx, ctx.PostForm("visibility"))
+	if !ok {
+		return
+	}
 
 	imageMemo := models.Memo{
-		OwnerID:  user.ID,
-		MemoType: "image",
-		Caption:  caption,
+		OwnerID:    user.ID,
+		MemoType:   "image",
+		Caption:    caption,
+		Audience:   audience,
+		Visibility: visibility,
 	}
 
 	// attempt to save image memo in repository
@@ -182,11 +205,21 @@ func (mh memoHandler) CreateVideoMemo(ctx *gin.Context) {
 
 	// Validate request data
 	caption := ctx.PostForm("caption")
+	audience, ok := parseAudience(ctx, ctx.PostForm("audience"))
+	if !ok {
+		return
+	}
+	visibility, ok := parseVisibility(ctx, ctx.PostForm("visibility"))
+	if !ok {
+		return
+	}
 
 	videoMemo := models.Memo{
-		OwnerID:  user.ID,
-		MemoType: "video",
-		Caption:  caption,
+		OwnerID:    user.ID,
+		MemoType:   "video",
+		Caption:    caption,
+		Audience:   audience,
+		Visibility: visibility,
 	}
 
 	// attempt to save video memo in repository
@@ -258,11 +291,21 @@ func (mh memoHandler) CreateAudioMemo(ctx *gin.Context) {
 
 	// Validate request data
 	caption := ctx.PostForm("caption")
+	audience, ok := parseAudience(ctx, ctx.PostForm("audience"))
+	if !ok {
+		return
+	}
+	visibility, ok := parseVisibility(ctx, ctx.PostForm("visibility"))
+	if !ok {
+		return
+	}
 
 	audioMemo := models.Memo{
-		OwnerID:  user.ID,
-		MemoType: "audio",
-		Caption:  caption,
+		OwnerID:    user.ID,
+		MemoType:   "audio",
+		Caption:    caption,
+		Audience:   audience,
+		Visibility: visibility,
 	}
 
 	// attempt to save audio memo in repository
@@ -343,15 +386,35 @@ func (mh memoHandler) GetMemo(ctx *gin.Context) {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
 			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
-		case errors.Is(err, repository.ErrRecordDeleted):
+		// only the owner of a deleted memo is shown what it was
+		case errors.Is(err, repository.ErrRecordDeleted) && memo.OwnerID == user.ID:
 			data := response.MemoResponseFromModel(memo)
 			helpers.HandleLogicalDeleteError(ctx, data, err)
+		case errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
 		default:
 			helpers.HandleInternalServerError(ctx, err)
 		}
 		return
 	}
 
+	// memos of users blocking or blocked by the viewer are hidden from them, as are the memos of private users
+	// the viewer does not follow and memos the viewer may not see by their visibility or audience
+	blocked, err := mh.app.Repositories.Social.IsBlocked(user.ID, memo.OwnerID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	visible, err := canViewMemo(mh.app, user.ID, memo)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if blocked || !visible {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		return
+	}
+
 	// return memo
 	ctx.JSON(
 		http.StatusOK,
@@ -373,6 +436,10 @@ func (mh memoHandler) LikeMemo(ctx *gin.Context) {
 		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrMemoIDQueryMissing)
 	}
 
+	if !ensureMemoAccessible(mh.app, ctx, user.ID, memoID) {
+		return
+	}
+
 	_, err := mh.app.Repositories.Memo.LikeMemo(user.ID, memoID)
 	if err != nil {
 		switch {
@@ -441,6 +508,10 @@ func (mh memoHandler) ShareMemo(ctx *gin.Context) {
 		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrMemoIDQueryMissing)
 	}
 
+	if !ensureMemoAccessible(mh.app, ctx, user.ID, memoID) {
+		return
+	}
+
 	_, err := mh.app.Repositories.Memo.ShareMemo(user.ID, memoID)
 	if err != nil {
 		switch {
@@ -533,7 +604,7 @@ func (mh memoHandler) GetAllMemos(ctx *gin.Context) {
 	}
 
 	// retrieve list of memos by followed users from the database
-	memos, err := mh.app.Repositories.Memo.GetAllMemos(page, pageSize)
+	memos, err := mh.app.Repositories.Memo.GetAllMemos(user.ID, page, pageSize)
 	if err != nil {
 		switch {
 		default:
@@ -652,7 +723,186 @@ func (mh memoHandler) DeleteMemo(ctx *gin.Context) {
 	)
 }
 
+// EditMemo changes the content of a text memo, or the caption and transcript of a media memo, of the authenticated user.
+// The memo as it was is kept as a revision, and the version of the memo being edited must be sent along.
+func (mh memoHandler) EditMemo(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	requestBody := request.EditMemo{}
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	memo, err := mh.app.Repositories.Memo.GetMemo(ctx.Param("memoID"))
+	if err != nil || memo.OwnerID != user.ID {
+		switch {
+		// the memos of other users are reported as not found, whether the user may see them or not
+		case err == nil, errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	// the content of media memos is their file, which cannot be edited
+	switch {
+	case requestBody.Content == nil && requestBody.Caption == nil && requestBody.Transcript == nil:
+		helpers.HandleValidationError(ctx, errors.New("content, caption or transcript is required"))
+		return
+	case memo.MemoType == "text" && (requestBody.Caption != nil || requestBody.Transcript != nil):
+		helpers.HandleValidationError(ctx, errors.New("text memos have no caption or transcript"))
+		return
+	case memo.MemoType != "text" && requestBody.Content != nil:
+		helpers.HandleValidationError(ctx, errors.New("only the caption and transcript of media memos can be edited"))
+		return
+	}
+
+	editedMemo, err := mh.app.Repositories.Memo.Edit(memo.ID, requestBody.ApplyTo(memo))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
+		case errors.Is(err, repository.ErrConcurrentUpdate):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"data":    response.MemoResponseFromModel(editedMemo),
+			"message": "Memo was successfully edited.",
+		},
+	)
+}
+
+// GetMemoRevisions fetches the earlier versions of a memo, most recent first, for users who may see the memo.
+func (mh memoHandler) GetMemoRevisions(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	memo, err := mh.app.Repositories.Memo.GetMemo(ctx.Param("memoID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	// the revisions of a memo are hidden from the users the memo itself is hidden from
+	blocked, err := mh.app.Repositories.Social.IsBlocked(user.ID, memo.OwnerID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	visible, err := canViewMemo(mh.app, user.ID, memo)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if blocked || !visible {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		return
+	}
+
+	revisions, err := mh.app.Repositories.Memo.GetRevisions(memo.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.MultipleMemoRevisionResponseFromModel(revisions),
+	)
+}
+
+// UpdateVisibility changes who may see a memo of the authenticated user.
+func (mh memoHandler) UpdateVisibility(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	requestBody := request.MemoVisibility{}
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	memo, err := mh.app.Repositories.Memo.GetMemo(ctx.Param("memoID"))
+	if err != nil || memo.OwnerID != user.ID {
+		switch {
+		// the memos of other users are reported as not found, whether the user may see them or not
+		case err == nil, errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	memo.Visibility = *requestBody.Visibility
+	updatedMemo, err := mh.app.Repositories.Memo.Update(memo.ID, memo)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
+		case errors.Is(err, repository.ErrConcurrentUpdate):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"data":    response.MemoResponseFromModel(updatedMemo),
+			"message": "Memo visibility was successfully updated.",
+		},
+	)
+}
+
 // GetMemosByOwnerID fetches all memos owned by a user with matching ID.
+// The memos of private users are only listed for their approved followers.
 func (mh memoHandler) GetMemosByOwnerID(ctx *gin.Context) {
 	user := helpers.ContextGetUser(ctx)
 
@@ -691,8 +941,18 @@ func (mh memoHandler) GetMemosByOwnerID(ctx *gin.Context) {
 		return
 	}
 
+	visible, err := canViewContent(mh.app, user.ID, ownerID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if !visible {
+		helpers.HandleErrorResponse(ctx, http.StatusForbidden, repository.ErrPrivateAccount)
+		return
+	}
+
 	// retrieve list of memos by followed users from the database
-	memos, err := mh.app.Repositories.Memo.GetMemosByOwnerID(ownerID, page, pageSize)
+	memos, err := mh.app.Repositories.Memo.GetMemosByOwnerID(ownerID, user.ID, page, pageSize)
 	if err != nil {
 		switch {
 		default:
@@ -746,7 +1006,7 @@ func (mh memoHandler) GetOwnMemos(ctx *gin.Context) {
 	}
 
 	// retrieve list of memos by followed users from the database
-	memos, err := mh.app.Repositories.Memo.GetMemosByOwnerID(user.ID, page, pageSize)
+	memos, err := mh.app.Repositories.Memo.GetMemosByOwnerID(user.ID, user.ID, page, pageSize)
 	if err != nil {
 		switch {
 		default:
@@ -760,3 +1020,31 @@ func (mh memoHandler) GetOwnMemos(ctx *gin.Context) {
 		http.StatusOK,
 		response.MultipleMemoResponseFromModel(memos))
 }
+
+// parseAudience reads the audience of a new memo, which is everyone unless stated otherwise.
+// An error response is written when false is returned.
+func parseAudience(ctx *gin.Context, audience string) (string, bool) {
+	switch audience {
+	case "", models.AudienceEveryone:
+		return models.AudienceEveryone, true
+	case models.AudienceCloseFriends:
+		return models.AudienceCloseFriends, true
+	default:
+		helpers.HandleValidationError(ctx, errors.New("audience must be either everyone or close_friends"))
+		return "", false
+	}
+}
+
+// parseVisibility reads the visibility of a memo, which is public unless stated otherwise.
+// An error response is written when false is returned.
+func parseVisibility(ctx *gin.Context, visibility string) (string, bool) {
+	switch visibility {
+	case "", models.VisibilityPublic:
+		return models.VisibilityPublic, true
+	case models.VisibilityFollowers, models.VisibilityUnlisted, models.VisibilityPrivate:
+		return visibility, true
+	default:
+		helpers.HandleValidationError(ctx, errors.New("visibility must be one of public, followers, unlisted or private"))
+		return "", false
+	}
+}
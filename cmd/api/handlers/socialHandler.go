@@ -7,11 +7,15 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/request"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/response"
 	"github.com/akinolaemmanuel49/memo-api/domain/models"
 	"github.com/akinolaemmanuel49/memo-api/domain/repository"
@@ -24,6 +28,24 @@ type SocialHandler interface {
 	CreateTextReply(ctx *gin.Context)
 	GetComments(ctx *gin.Context)
 	GetReplies(ctx *gin.Context)
+	GetFollowRequests(ctx *gin.Context)
+	GetSuggestions(ctx *gin.Context)
+	GetRelationship(ctx *gin.Context)
+	GetMutualFollowers(ctx *gin.Context)
+	ApproveFollowRequest(ctx *gin.Context)
+	RejectFollowRequest(ctx *gin.Context)
+	Block(ctx *gin.Context)
+	Unblock(ctx *gin.Context)
+	GetBlockedUsers(ctx *gin.Context)
+	Mute(ctx *gin.Context)
+	Unmute(ctx *gin.Context)
+	GetMutedUsers(ctx *gin.Context)
+	MuteWord(ctx *gin.Context)
+	UnmuteWord(ctx *gin.Context)
+	GetMutedWords(ctx *gin.Context)
+	AddCloseFriend(ctx *gin.Context)
+	RemoveCloseFriend(ctx *gin.Context)
+	GetCloseFriends(ctx *gin.Context)
 }
 
 type socialHandler struct {
@@ -35,6 +57,7 @@ func NewSocialHandler(app internal.Application) SocialHandler {
 }
 
 // Follow creates a new relationship between an authenticated user and another user.
+// Following a private user sends them a follow request instead, which they can approve or reject.
 func (sh socialHandler) Follow(ctx *gin.Context) {
 	// Fetch authenticated user from context and return authentication error if no user exists
 	user := helpers.ContextGetUser(ctx)
@@ -44,14 +67,34 @@ func (sh socialHandler) Follow(ctx *gin.Context) {
 		return
 	}
 
-	subjectID := ctx.Param("subjectID")
+	subjectID, ok := bindFollowID(ctx, request.FollowFieldSubjectID)
+	if !ok {
+		return
+	}
 	if subjectID == user.ID {
 		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrCheckFollow)
 		return
 	}
 
+	if !ensureNotBlocked(sh.app, ctx, user.ID, subjectID) {
+		return
+	}
+
+	subject, ok := findUser(sh.app, ctx, subjectID)
+	if !ok {
+		return
+	}
+
+	// the suggestions of the user are likely to include the subject
+	sh.app.Suggestions.Delete(user.ID)
+
+	if subject.Private {
+		sh.requestFollow(ctx, user, subject)
+		return
+	}
+
 	// attempt to follow a user
-	newFollow, err := sh.app.Repositories.Social.Follow(user.ID, subjectID)
+	newFollow, err := sh.app.Repositories.Social.Follow(user.ID, subject.ID)
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrDuplicateFollow):
@@ -64,25 +107,12 @@ func (sh socialHandler) Follow(ctx *gin.Context) {
 		return
 	}
 
-	followedUser, err := sh.app.Repositories.Users.GetById(newFollow.SubjectID)
-	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrRecordNotFound):
-			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
-
-		default:
-			fmt.Println(err)
-			helpers.HandleInternalServerError(ctx, err)
-		}
-		return
-	}
-
 	responseMessage := gin.H{
 		"status":  "success",
 		"message": "User successfully followed.",
 		"data": gin.H{
-			"userID":     followedUser.ID,
-			"username":   followedUser.Username,
+			"userID":     subject.ID,
+			"username":   subject.Username,
 			"followedAt": newFollow.CreatedAt,
 		},
 	}
@@ -93,7 +123,47 @@ func (sh socialHandler) Follow(ctx *gin.Context) {
 	)
 }
 
+// requestFollow sends a follow request to a private user.
+func (sh socialHandler) requestFollow(ctx *gin.Context, user models.User, subject models.User) {
+	relationship, err := sh.app.Repositories.Social.GetRelationship(user.ID, subject.ID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if relationship.Following {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrDuplicateFollow)
+		return
+	}
+
+	newRequest, err := sh.app.Repositories.Social.RequestFollow(user.ID, subject.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateFollowRequest):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		case errors.Is(err, repository.ErrCheckFollow):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusAccepted,
+		gin.H{
+			"status":  "success",
+			"message": "Follow request successfully sent.",
+			"data": gin.H{
+				"userID":      subject.ID,
+				"username":    subject.Username,
+				"requestedAt": newRequest.CreatedAt,
+			},
+		},
+	)
+}
+
 // Unfollow deletes an existing relationship between an authenticated user and another user.
+// A follow request awaiting approval is withdrawn instead.
 func (sh socialHandler) Unfollow(ctx *gin.Context) {
 	// fetch authenticated user from context and return authentication error if no user exists
 	user := helpers.ContextGetUser(ctx)
@@ -102,13 +172,42 @@ func (sh socialHandler) Unfollow(ctx *gin.Context) {
 		return
 	}
 
-	// retrieve the user id for the user to follow from query params
-	subjectID := ctx.Param("subjectID")
+	// retrieve the user id for the user to unfollow from the request body
+	subjectID, ok := bindFollowID(ctx, request.FollowFieldSubjectID)
+	if !ok {
+		return
+	}
 	if subjectID == user.ID {
 		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrCheckFollow)
 		return
 	}
 
+	relationship, err := sh.app.Repositories.Social.GetRelationship(user.ID, subjectID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	if !relationship.Following && relationship.Requested {
+		if err := sh.app.Repositories.Social.DeleteFollowRequest(user.ID, subjectID); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrRecordNotFound):
+				helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("follow request not found"))
+			default:
+				helpers.HandleInternalServerError(ctx, err)
+			}
+			return
+		}
+
+		ctx.JSON(
+			http.StatusOK,
+			gin.H{
+				"status":  "success",
+				"message": "Follow request successfully withdrawn.",
+			},
+		)
+		return
+	}
+
 	// unfollow a user
 	newUnfollow, err := sh.app.Repositories.Social.Unfollow(user.ID, subjectID)
 	if err != nil {
@@ -149,6 +248,188 @@ func (sh socialHandler) Unfollow(ctx *gin.Context) {
 	)
 }
 
+// GetRelationship retrieves how the authenticated user and another user are connected.
+func (sh socialHandler) GetRelationship(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	subject, ok := findUser(sh.app, ctx, ctx.Param("userID"))
+	if !ok {
+		return
+	}
+
+	relationship, err := sh.app.Repositories.Social.GetRelationship(user.ID, subject.ID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.RelationshipResponseFromModel(relationship),
+	})
+}
+
+// GetMutualFollowers retrieves the users following both the authenticated user and another user, most followed first.
+// The followers of private users are only listed for their approved followers.
+func (sh socialHandler) GetMutualFollowers(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	subject, ok := findUser(sh.app, ctx, ctx.Param("userID"))
+	if !ok {
+		return
+	}
+	if !ensureCanViewConnections(sh.app, ctx, user.ID, subject.ID) {
+		return
+	}
+
+	users, err := sh.app.Repositories.Social.GetMutualFollowers(user.ID, subject.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultiplePublicUserResponseFromModel(users),
+	})
+}
+
+// GetSuggestions retrieves users for the authenticated user to follow, best suggestions first.
+// Suggestions are worked out at most once per helpers.SuggestionCacheDuration, unless the user follows, blocks
+// or mutes someone in the meantime.
+func (sh socialHandler) GetSuggestions(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	suggestions, ok := sh.app.Suggestions.Get(user.ID)
+	if !ok {
+		var err error
+		activeSince := time.Now().UTC().Add(-helpers.SuggestionActivityWindow)
+		suggestions, err = sh.app.Repositories.Social.GetSuggestions(user.ID, activeSince, helpers.SuggestionLimit)
+		if err != nil {
+			helpers.HandleInternalServerError(ctx, err)
+			return
+		}
+		sh.app.Suggestions.Set(user.ID, suggestions)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleSuggestionResponseFromModel(suggestions),
+	})
+}
+
+// GetFollowRequests retrieves the users waiting for the authenticated user to approve their follow requests,
+// oldest request first.
+func (sh socialHandler) GetFollowRequests(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := sh.app.Repositories.Social.GetFollowRequests(user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultiplePublicUserResponseFromModel(users),
+	})
+}
+
+// ApproveFollowRequest lets the user who sent a follow request to the authenticated user follow them.
+func (sh socialHandler) ApproveFollowRequest(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	followerID, ok := bindFollowID(ctx, request.FollowFieldFollowerID)
+	if !ok {
+		return
+	}
+
+	newFollow, err := sh.app.Repositories.Social.ApproveFollowRequest(user.ID, followerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("follow request not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "Follow request successfully approved.",
+			"data": gin.H{
+				"userID":     newFollow.FollowerID,
+				"followedAt": newFollow.CreatedAt,
+			},
+		},
+	)
+}
+
+// RejectFollowRequest turns down a follow request sent to the authenticated user.
+func (sh socialHandler) RejectFollowRequest(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	followerID, ok := bindFollowID(ctx, request.FollowFieldFollowerID)
+	if !ok {
+		return
+	}
+
+	if err := sh.app.Repositories.Social.DeleteFollowRequest(followerID, user.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("follow request not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "Follow request successfully rejected.",
+		},
+	)
+}
+
 // CreateTextComment creates a new instance of a text based comment.
 func (sh socialHandler) CreateTextComment(ctx *gin.Context) {
 	// Fetch authenticated user from context and return authentication error if no user exists
@@ -160,6 +441,9 @@ func (sh socialHandler) CreateTextComment(ctx *gin.Context) {
 	}
 
 	memoID := ctx.Param("memoID")
+	if !ensureMemoAccessible(sh.app, ctx, user.ID, memoID) {
+		return
+	}
 
 	comment := ctx.PostForm("comment")
 
@@ -196,6 +480,10 @@ func (sh socialHandler) CreateImageComment(ctx *gin.Context) {
 	}
 
 	memoID := ctx.Param("memoID")
+	if !ensureMemoAccessible(sh.app, ctx, user.ID, memoID) {
+		return
+	}
+
 	// Validate request data
 	caption := ctx.PostForm("caption")
 
@@ -279,6 +567,10 @@ func (sh socialHandler) CreateAudioComment(ctx *gin.Context) {
 	}
 
 	memoID := ctx.Param("memoID")
+	if !ensureMemoAccessible(sh.app, ctx, user.ID, memoID) {
+		return
+	}
+
 	// Validate request data
 	caption := ctx.PostForm("caption")
 	transcript := ctx.PostForm("transcript")
@@ -369,6 +661,10 @@ func (sh socialHandler) CreateVideoComment(ctx *gin.Context) {
 	}
 
 	memoID := ctx.Param("memoID")
+	if !ensureMemoAccessible(sh.app, ctx, user.ID, memoID) {
+		return
+	}
+
 	// Validate request data
 	caption := ctx.PostForm("caption")
 	transcript := ctx.PostForm("transcript")
@@ -477,7 +773,15 @@ func (sh socialHandler) CreateTextReply(ctx *gin.Context) {
 		return
 	}
 
+	if !ensureNotBlocked(sh.app, ctx, user.ID, parentComment.OwnerID) {
+		return
+	}
+
 	memoID := parentComment.MemoID
+	if !ensureMemoAccessible(sh.app, ctx, user.ID, memoID) {
+		return
+	}
+
 	sqlParentID := sql.NullString{
 		String: parentID,
 		Valid:  true,
@@ -549,8 +853,12 @@ func (sh socialHandler) GetComments(ctx *gin.Context) {
 		return
 	}
 
+	if !ensureMemoAccessible(sh.app, ctx, user.ID, memoID) {
+		return
+	}
+
 	// Fetch comments by memoID
-	comments, err := sh.app.Repositories.Social.GetCommentsByMemoID(memoID, page, pageSize)
+	comments, err := sh.app.Repositories.Social.GetCommentsByMemoID(memoID, user.ID, page, pageSize)
 	if err != nil {
 		helpers.HandleInternalServerError(ctx, err)
 		return
@@ -605,8 +913,22 @@ func (sh socialHandler) GetReplies(ctx *gin.Context) {
 		return
 	}
 
+	parentComment, err := sh.app.Repositories.Social.GetComment(commentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+	if !ensureMemoAccessible(sh.app, ctx, user.ID, parentComment.MemoID) {
+		return
+	}
+
 	// Fetch replies by commentID
-	replies, err := sh.app.Repositories.Social.GetRepliesByParentID(commentID, page, pageSize)
+	replies, err := sh.app.Repositories.Social.GetRepliesByParentID(commentID, user.ID, page, pageSize)
 	if err != nil {
 		helpers.HandleInternalServerError(ctx, err)
 		return
@@ -620,3 +942,627 @@ func (sh socialHandler) GetReplies(ctx *gin.Context) {
 		"data":   returned,
 	})
 }
+
+// Block blocks another user, removing any follow between them and the authenticated user.
+// Blocked users and the users blocking them cannot see each other's memos and comments or interact with them.
+func (sh socialHandler) Block(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	blockedID := ctx.Param("userID")
+	if blockedID == user.ID {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrCheckBlock)
+		return
+	}
+
+	blockedUser, ok := findUser(sh.app, ctx, blockedID)
+	if !ok {
+		return
+	}
+
+	newBlock, err := sh.app.Repositories.Social.Block(user.ID, blockedUser.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateBlock):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		case errors.Is(err, repository.ErrCheckBlock):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+	sh.app.Suggestions.Delete(user.ID)
+	sh.app.Suggestions.Delete(blockedUser.ID)
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully blocked.",
+			"data": gin.H{
+				"userID":    blockedUser.ID,
+				"username":  blockedUser.Username,
+				"blockedAt": newBlock.CreatedAt,
+			},
+		},
+	)
+}
+
+// Unblock lifts a block placed by the authenticated user. Follows removed by the block are not restored.
+func (sh socialHandler) Unblock(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	if err := sh.app.Repositories.Social.Unblock(user.ID, ctx.Param("userID")); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user is not blocked"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully unblocked.",
+		},
+	)
+}
+
+// GetBlockedUsers retrieves the users blocked by the authenticated user, most recently blocked first.
+func (sh socialHandler) GetBlockedUsers(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := sh.app.Repositories.Social.GetBlockedUsers(user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultiplePublicUserResponseFromModel(users),
+	})
+}
+
+// Mute hides the memos and comments of another user from the authenticated user's feeds and comment listings,
+// without unfollowing them.
+func (sh socialHandler) Mute(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	mutedID := ctx.Param("userID")
+	if mutedID == user.ID {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrCheckMute)
+		return
+	}
+
+	mutedUser, ok := findUser(sh.app, ctx, mutedID)
+	if !ok {
+		return
+	}
+
+	newMute, err := sh.app.Repositories.Social.Mute(user.ID, mutedUser.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateMute):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		case errors.Is(err, repository.ErrCheckMute):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+	sh.app.Suggestions.Delete(user.ID)
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully muted.",
+			"data": gin.H{
+				"userID":   mutedUser.ID,
+				"username": mutedUser.Username,
+				"mutedAt":  newMute.CreatedAt,
+			},
+		},
+	)
+}
+
+// Unmute lifts a mute placed by the authenticated user.
+func (sh socialHandler) Unmute(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	if err := sh.app.Repositories.Social.Unmute(user.ID, ctx.Param("userID")); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user is not muted"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully unmuted.",
+		},
+	)
+}
+
+// GetMutedUsers retrieves the users muted by the authenticated user, most recently muted first.
+func (sh socialHandler) GetMutedUsers(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := sh.app.Repositories.Social.GetMutedUsers(user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultiplePublicUserResponseFromModel(users),
+	})
+}
+
+// MuteWord hides memos and comments containing a word, phrase or hashtag from the authenticated user,
+// until expiresAt if it is given.
+func (sh socialHandler) MuteWord(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	// validate request
+	requestBody := request.MutedWord{}
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	mutedWord := requestBody.ToModel()
+	mutedWord.UserID = user.ID
+	if mutedWord.Word == "" {
+		helpers.HandleValidationError(ctx, errors.New("word must not be blank"))
+		return
+	}
+	if mutedWord.ExpiresAt.Valid && !mutedWord.ExpiresAt.Time.After(time.Now()) {
+		helpers.HandleValidationError(ctx, errors.New("expiresAt must be in the future"))
+		return
+	}
+
+	newMutedWord, err := sh.app.Repositories.Social.MuteWord(&mutedWord)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateMutedWord):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusCreated,
+		response.MutedWordResponseFromModel(newMutedWord),
+	)
+}
+
+// UnmuteWord removes a muted word of the authenticated user.
+func (sh socialHandler) UnmuteWord(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	mutedWordID := ctx.Param("id")
+	if _, err := uuid.Parse(mutedWordID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		return
+	}
+
+	if err := sh.app.Repositories.Social.UnmuteWord(user.ID, mutedWordID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "Word successfully unmuted.",
+		},
+	)
+}
+
+// GetMutedWords retrieves the unexpired muted words of the authenticated user, most recently muted first.
+func (sh socialHandler) GetMutedWords(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	mutedWords, err := sh.app.Repositories.Social.GetMutedWords(user.ID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleMutedWordResponseFromModel(mutedWords),
+	})
+}
+
+// AddCloseFriend adds another user to the close friends of the authenticated user,
+// who can then see the memos the authenticated user shares with close friends only.
+func (sh socialHandler) AddCloseFriend(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	friendID := ctx.Param("userID")
+	if friendID == user.ID {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, repository.ErrCheckCloseFriend)
+		return
+	}
+
+	friend, ok := findUser(sh.app, ctx, friendID)
+	if !ok {
+		return
+	}
+	if !ensureNotBlocked(sh.app, ctx, user.ID, friend.ID) {
+		return
+	}
+
+	newCloseFriend, err := sh.app.Repositories.Social.AddCloseFriend(user.ID, friend.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateCloseFriend):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		case errors.Is(err, repository.ErrCheckCloseFriend):
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully added to close friends.",
+			"data": gin.H{
+				"userID":   friend.ID,
+				"username": friend.Username,
+				"addedAt":  newCloseFriend.CreatedAt,
+			},
+		},
+	)
+}
+
+// RemoveCloseFriend removes a user from the close friends of the authenticated user.
+func (sh socialHandler) RemoveCloseFriend(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	friendID := ctx.Param("userID")
+	if _, err := uuid.Parse(friendID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user is not a close friend"))
+		return
+	}
+
+	if err := sh.app.Repositories.Social.RemoveCloseFriend(user.ID, friendID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user is not a close friend"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"status":  "success",
+			"message": "User successfully removed from close friends.",
+		},
+	)
+}
+
+// GetCloseFriends retrieves the close friends of the authenticated user, most recently added first.
+func (sh socialHandler) GetCloseFriends(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := sh.app.Repositories.Social.GetCloseFriends(user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultiplePublicUserResponseFromModel(users),
+	})
+}
+
+// pageParams reads the page and pageSize query parameters.
+// An error response is written when false is returned.
+func pageParams(ctx *gin.Context) (page int, pageSize int, ok bool) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", helpers.DefaultPage))
+	if err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("invalid value for page parameter"))
+		return 0, 0, false
+	}
+	pageSize, err = strconv.Atoi(ctx.DefaultQuery("pageSize", helpers.DefaultPageSize))
+	if err != nil || pageSize < 1 {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("invalid value for pageSize parameter"))
+		return 0, 0, false
+	}
+
+	return page, pageSize, true
+}
+
+// ensureNotBlocked writes a forbidden response and returns false if either user has blocked the other.
+// An empty otherUserID, such as the owner of an anonymized comment, is never blocked.
+func ensureNotBlocked(app internal.Application, ctx *gin.Context, userID, otherUserID string) bool {
+	if otherUserID == "" || otherUserID == userID {
+		return true
+	}
+
+	blocked, err := app.Repositories.Social.IsBlocked(userID, otherUserID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return false
+	}
+	if blocked {
+		helpers.HandleErrorResponse(ctx, http.StatusForbidden, repository.ErrBlocked)
+		return false
+	}
+
+	return true
+}
+
+// ensureMemoAccessible writes an error response and returns false if a user may not see or interact with a memo,
+// because the memo is missing, deleted or suspended, the user and the owner of the memo have blocked one another
+// or canViewMemo does not allow it.
+func ensureMemoAccessible(app internal.Application, ctx *gin.Context, userID, memoID string) bool {
+	if _, err := uuid.Parse(memoID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		return false
+	}
+
+	memo, err := app.Repositories.Memo.GetMemo(memoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+			return false
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+			return false
+		}
+	}
+
+	if !ensureNotBlocked(app, ctx, userID, memo.OwnerID) {
+		return false
+	}
+
+	visible, err := canViewMemo(app, userID, memo)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return false
+	}
+	if !visible {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		return false
+	}
+
+	return true
+}
+
+// ensureCanViewConnections writes an error response and returns false if a user may not see the followers and
+// following of another user, because they have blocked one another or the other user is private and not followed.
+func ensureCanViewConnections(app internal.Application, ctx *gin.Context, userID, otherUserID string) bool {
+	if !ensureNotBlocked(app, ctx, userID, otherUserID) {
+		return false
+	}
+
+	visible, err := canViewContent(app, userID, otherUserID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return false
+	}
+	if !visible {
+		helpers.HandleErrorResponse(ctx, http.StatusForbidden, repository.ErrPrivateAccount)
+		return false
+	}
+
+	return true
+}
+
+// canViewContent reports whether the viewer may see the memos, followers and following of a user.
+// Private users share them with their approved followers only.
+func canViewContent(app internal.Application, viewerID, ownerID string) (bool, error) {
+	if viewerID == ownerID {
+		return true, nil
+	}
+
+	owner, err := app.Repositories.Users.GetById(ownerID)
+	if err != nil {
+		switch {
+		// there is nothing to hide for users that do not exist
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return true, nil
+		default:
+			return false, err
+		}
+	}
+	if !owner.Private {
+		return true, nil
+	}
+
+	relationship, err := app.Repositories.Social.GetRelationship(viewerID, ownerID)
+	if err != nil {
+		return false, err
+	}
+
+	return relationship.Following, nil
+}
+
+// canViewMemo reports whether the viewer may see a memo. The memos of suspended users are hidden from everyone else.
+// Other memos are shown according to their visibility: private memos to their owner alone, followers-only memos
+// to approved followers of the owner and other memos under the restrictions of canViewContent.
+// Memos for close friends are further limited to the close friends of the owner.
+func canViewMemo(app internal.Application, viewerID string, memo models.Memo) (bool, error) {
+	if viewerID == memo.OwnerID {
+		return true, nil
+	}
+
+	owner, err := app.Repositories.Users.GetById(memo.OwnerID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) && !errors.Is(err, repository.ErrRecordDeleted) {
+		return false, err
+	}
+	if owner.Suspended {
+		return false, nil
+	}
+
+	switch memo.Visibility {
+	case models.VisibilityPrivate:
+		return false, nil
+	case models.VisibilityFollowers:
+		relationship, err := app.Repositories.Social.GetRelationship(viewerID, memo.OwnerID)
+		if err != nil || !relationship.Following {
+			return false, err
+		}
+	default:
+		visible, err := canViewContent(app, viewerID, memo.OwnerID)
+		if err != nil || !visible {
+			return false, err
+		}
+	}
+
+	if memo.Audience != models.AudienceCloseFriends {
+		return true, nil
+	}
+
+	return app.Repositories.Social.IsCloseFriend(memo.OwnerID, viewerID)
+}
+
+// findUser retrieves the user with matching id for another user to see or interact with.
+// Deleted and suspended users are reported as not found. An error response is written when false is returned.
+func findUser(app internal.Application, ctx *gin.Context, userID string) (models.User, bool) {
+	if _, err := uuid.Parse(userID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		return models.User{}, false
+	}
+
+	user, err := app.Repositories.Users.GetById(userID)
+	if err != nil || user.Deleted || user.Suspended {
+		switch {
+		case err == nil, errors.Is(err, repository.ErrRecordNotFound), errors.Is(err, repository.ErrRecordDeleted):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return models.User{}, false
+	}
+
+	return user, true
+}
+
+// bindFollowID reads the user ID in the given field of a request.Follow body.
+// An error response is written when false is returned.
+func bindFollowID(ctx *gin.Context, field int) (string, bool) {
+	requestBody := request.Follow{}
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return "", false
+	}
+
+	if err := requestBody.ValidateRequired(field); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return "", false
+	}
+
+	followRequest := requestBody.ToModel()
+	userID := followRequest.SubjectID
+	if field == request.FollowFieldFollowerID {
+		userID = followRequest.FollowerID
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("user not found"))
+		return "", false
+	}
+
+	return userID, true
+}
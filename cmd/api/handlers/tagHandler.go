@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/request"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/models/response"
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+type TagHandler interface {
+	Search(ctx *gin.Context)
+	GetMemos(ctx *gin.Context)
+}
+
+type tagHandler struct {
+	app internal.Application
+}
+
+func NewTagHandler(app internal.Application) TagHandler {
+	return tagHandler{app: app}
+}
+
+// Search suggests the hashtags starting with the q query parameter most used in memos the authenticated user can see,
+// for autocompletion.
+func (th tagHandler) Search(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	prefix := request.Hashtag(ctx.Query("q")).Name()
+	if prefix == "" {
+		helpers.HandleValidationError(ctx, errors.New("q must be a hashtag of letters, digits and underscores"))
+		return
+	}
+
+	hashtags, err := th.app.Repositories.Hashtags.Search(prefix, user.ID, helpers.HashtagSearchLimit)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleHashtagResponseFromModel(hashtags),
+	})
+}
+
+// GetMemos fetches the memos tagged with a hashtag, newest first, as the authenticated user is allowed to see them.
+func (th tagHandler) GetMemos(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	name := request.Hashtag(ctx.Param("tag")).Name()
+	if name == "" {
+		helpers.HandleValidationError(ctx, errors.New("tag must be a hashtag of letters, digits and underscores"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	memos, err := th.app.Repositories.Hashtags.GetMemos(name, user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.MultipleMemoResponseFromModel(memos),
+	})
+}
@@ -1,12 +1,22 @@
 package handlers
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"os"
 	"reflect"
+	"runtime/debug"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
@@ -17,12 +27,23 @@ import (
 
 type UserHandler interface {
 	Get(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	GetByUsername(ctx *gin.Context)
 	GetAll(ctx *gin.Context)
+	Search(ctx *gin.Context)
 	Update(ctx *gin.Context)
 	GetFollowers(ctx *gin.Context)
 	GetFollowing(ctx *gin.Context)
+	GetFollowersByID(ctx *gin.Context)
+	GetFollowingByID(ctx *gin.Context)
 	DeleteAvatar(ctx *gin.Context)
 	Delete(ctx *gin.Context)
+	CreateToken(ctx *gin.Context)
+	GetTokens(ctx *gin.Context)
+	RevokeToken(ctx *gin.Context)
+	Export(ctx *gin.Context)
+	GetExport(ctx *gin.Context)
+	DownloadExport(ctx *gin.Context)
 }
 
 type userHandler struct {
@@ -50,6 +71,67 @@ func (uh userHandler) Get(ctx *gin.Context) {
 
 }
 
+// GetByID returns the public profile of the user with matching id, along with how the viewer relates to them.
+func (uh userHandler) GetByID(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, repository.ErrRecordNotFound)
+		return
+	}
+
+	user, err := uh.app.Repositories.Users.GetById(userID)
+	uh.writeProfile(ctx, user, err)
+}
+
+// GetByUsername returns the public profile of the user with matching username, ignoring case,
+// along with how the viewer relates to them.
+func (uh userHandler) GetByUsername(ctx *gin.Context) {
+	user, err := uh.app.Repositories.Users.GetByUsername(ctx.Param("username"))
+	uh.writeProfile(ctx, user, err)
+}
+
+// writeProfile writes the public profile of a user retrieved for the viewer in context.
+// Deleted users are reported like deleted memos are, suspended users are hidden as if they did not exist.
+func (uh userHandler) writeProfile(ctx *gin.Context, user models.User, err error) {
+	viewer := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(viewer, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	if err == nil {
+		switch {
+		case user.Deleted:
+			err = repository.ErrRecordDeleted
+		case user.Suspended:
+			err = repository.ErrRecordNotFound
+		}
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
+		case errors.Is(err, repository.ErrRecordDeleted):
+			data := response.User{ID: user.ID, Deleted: true}
+			helpers.HandleLogicalDeleteError(ctx, data, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	relationship, err := uh.app.Repositories.Social.GetRelationship(viewer.ID, user.ID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.ProfileResponseFromModel(user, relationship),
+	)
+}
+
 // GetAll retrieves a list of users.
 func (uh userHandler) GetAll(ctx *gin.Context) {
 	// fetch authenticated user from context and return authentication error if no user exists
@@ -105,8 +187,46 @@ func (uh userHandler) GetAll(ctx *gin.Context) {
 	)
 }
 
+// Search retrieves a page of users whose username or full name contains the q query parameter,
+// best matches first.
+func (uh userHandler) Search(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	searchString := strings.TrimSpace(ctx.Query("q"))
+	if searchString == "" {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("q parameter is required"))
+		return
+	}
+	if len(searchString) > helpers.MaxSearchLength {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, fmt.Errorf("q parameter must be at most %d characters long", helpers.MaxSearchLength))
+		return
+	}
+
+	// retrieve query params for pagination
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := uh.app.Repositories.Users.GetBySearchString(searchString, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.MultiplePublicUserResponseFromModel(users),
+	)
+}
+
 // Update updates and returns the profile of an authenticated user.
-// Updatable details include: username, first name, last name, storage, status, about.
+// Updatable details include: username, first name, last name, storage, status, about, private.
+// Pending follow requests are approved when a private user becomes public.
 func (uh userHandler) Update(ctx *gin.Context) {
 	// Fetch authenticated user from context
 	user := helpers.ContextGetUser(ctx)
@@ -125,9 +245,18 @@ func (uh userHandler) Update(ctx *gin.Context) {
 	password := ctx.PostForm("password")
 	status := ctx.PostForm("status")
 	about := ctx.PostForm("about")
+	private := user.Private
+	if privateStr := ctx.PostForm("private"); privateStr != "" {
+		isPrivate, err := strconv.ParseBool(privateStr)
+		if err != nil {
+			helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("invalid value for private"))
+			return
+		}
+		private = isPrivate
+	}
 
 	if password != "" {
-		if err := helpers.HashPassword(&password); err != nil {
+		if err := uh.app.Passwords.HashPassword(&password); err != nil {
 			helpers.HandleInternalServerError(ctx, err)
 			return
 		}
@@ -167,8 +296,10 @@ func (uh userHandler) Update(ctx *gin.Context) {
 	if username != "" {
 		updatedUser.Username = username
 	}
-	if email != "" {
+	if email != "" && email != user.Email {
+		// a new address has to be verified again
 		updatedUser.Email = email
+		updatedUser.IsActivated = false
 	}
 	if firstName != "" {
 		updatedUser.FirstName = firstName
@@ -185,10 +316,12 @@ func (uh userHandler) Update(ctx *gin.Context) {
 	if about != "" {
 		updatedUser.About = about
 	}
+	updatedUser.Private = private
 
 	updatedUser.AvatarURL = avatarURL
 
-	if _, err := uh.app.Repositories.Users.Update(user.ID, updatedUser); err != nil {
+	savedUser, err := uh.app.Repositories.Users.Update(user.ID, updatedUser)
+	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
 			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, err)
@@ -205,6 +338,17 @@ func (uh userHandler) Update(ctx *gin.Context) {
 		return
 	}
 
+	if savedUser.Email != user.Email {
+		go sendVerificationEmail(uh.app, savedUser)
+	}
+
+	if user.Private && !savedUser.Private {
+		if err := uh.app.Repositories.Social.ApproveAllFollowRequests(savedUser.ID); err != nil {
+			helpers.HandleInternalServerError(ctx, err)
+			return
+		}
+	}
+
 	ctx.JSON(
 		http.StatusOK,
 		gin.H{
@@ -323,7 +467,53 @@ func (uh userHandler) GetFollowing(ctx *gin.Context) {
 	)
 }
 
-// Delete performs a soft delete of a user instance.
+// GetFollowersByID retrieves a list of users following the user with matching id.
+// The followers of private users are only listed for their approved followers.
+func (uh userHandler) GetFollowersByID(ctx *gin.Context) {
+	uh.listConnections(ctx, uh.app.Repositories.Users.GetFollowersOfUser)
+}
+
+// GetFollowingByID retrieves a list of users being followed by the user with matching id.
+// The following of private users are only listed for their approved followers.
+func (uh userHandler) GetFollowingByID(ctx *gin.Context) {
+	uh.listConnections(ctx, uh.app.Repositories.Users.GetUsersFollowedBy)
+}
+
+// listConnections writes the users fetched for the user with matching id, if the viewer may see them.
+func (uh userHandler) listConnections(ctx *gin.Context, fetch func(id string, page, pageSize int) ([]models.User, error)) {
+	viewer := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(viewer, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	page, pageSize, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	user, ok := findUser(uh.app, ctx, ctx.Param("id"))
+	if !ok {
+		return
+	}
+	if !ensureCanViewConnections(uh.app, ctx, viewer.ID, user.ID) {
+		return
+	}
+
+	users, err := fetch(user.ID, page, pageSize)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.MultiplePublicUserResponseFromModel(users),
+	)
+}
+
+// Delete performs a soft delete of a user instance and schedules the user to be purged once the grace period
+// has passed. Logging in before then cancels the deletion.
 func (uh userHandler) Delete(ctx *gin.Context) {
 	user := helpers.ContextGetUser(ctx)
 	if reflect.DeepEqual(user, models.User{}) {
@@ -331,7 +521,8 @@ func (uh userHandler) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if _, err := uh.app.Repositories.Users.Delete(user.ID, user); err != nil {
+	purgeAfter := time.Now().UTC().Add(uh.app.Config.DeletionGracePeriod)
+	if _, err := uh.app.Repositories.Users.Delete(user.ID, user, purgeAfter); err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
 			helpers.HandleErrorResponse(ctx, http.StatusNotFound, err)
@@ -346,10 +537,17 @@ func (uh userHandler) Delete(ctx *gin.Context) {
 		return
 	}
 
+	// a login is needed to cancel the deletion, so existing sessions end here
+	if err := revokeAllSessions(uh.app, user.ID); err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
 	ctx.JSON(
 		http.StatusOK,
 		gin.H{
-			"message": "User was successfully deleted",
+			"message":    "User was successfully deleted, log in before the purge date to cancel",
+			"purgeAfter": purgeAfter,
 		},
 	)
 }
@@ -392,3 +590,382 @@ func (uh userHandler) DeleteAvatar(ctx *gin.Context) {
 		},
 	)
 }
+
+// CreateToken creates a personal access token for an authenticated user.
+// The token is only returned in this response, only its hash is stored.
+func (uh userHandler) CreateToken(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	// validate request
+	requestBody := struct {
+		Name      string     `json:"name" validate:"required,max=100"`
+		Scopes    []string   `json:"scopes" validate:"required,min=1,unique,dive,oneof=memo:read memo:write social:read social:write profile:read profile:write"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}{}
+
+	if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(requestBody); err != nil {
+		helpers.HandleValidationError(ctx, err)
+		return
+	}
+
+	var expiresAt sql.NullTime
+	if requestBody.ExpiresAt != nil {
+		if !requestBody.ExpiresAt.After(time.Now()) {
+			helpers.HandleValidationError(ctx, errors.New("expiresAt must be in the future"))
+			return
+		}
+		expiresAt = sql.NullTime{Time: requestBody.ExpiresAt.UTC(), Valid: true}
+	}
+
+	// generate token and store its hash
+	token, err := helpers.GenerateOpaqueToken()
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+	token = helpers.PersonalAccessTokenPrefix + token
+
+	accessToken, err := uh.app.Repositories.Tokens.CreatePersonalAccessToken(&models.PersonalAccessToken{
+		UserID:    user.ID,
+		Name:      requestBody.Name,
+		TokenHash: helpers.HashToken(token),
+		Scopes:    requestBody.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	tokenResponse := response.PersonalAccessTokenResponseFromModel(accessToken)
+	tokenResponse.Token = token
+	ctx.JSON(
+		http.StatusCreated,
+		tokenResponse,
+	)
+}
+
+// GetTokens lists the personal access tokens of an authenticated user.
+func (uh userHandler) GetTokens(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	accessTokens, err := uh.app.Repositories.Tokens.GetPersonalAccessTokensByUserID(user.ID)
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.MultiplePersonalAccessTokenResponseFromModel(accessTokens),
+	)
+}
+
+// RevokeToken revokes a personal access token of an authenticated user.
+func (uh userHandler) RevokeToken(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	tokenID := ctx.Param("id")
+	if _, err := uuid.Parse(tokenID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusBadRequest, errors.New("invalid token id"))
+		return
+	}
+
+	err := uh.app.Repositories.Tokens.RevokePersonalAccessToken(tokenID, user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("token not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "Token was successfully revoked",
+		},
+	)
+}
+
+// Export starts an export of everything an authenticated user owns.
+// The archive is built in the background and the download link is mailed to the user once it is ready.
+// The link is returned here too, to be used once the export has completed.
+func (uh userHandler) Export(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	// generate download token and store its hash
+	token, err := helpers.GenerateOpaqueToken()
+	if err != nil {
+		helpers.HandleInternalServerError(ctx, err)
+		return
+	}
+
+	export, err := uh.app.Repositories.DataExports.Create(&models.DataExport{
+		UserID:    user.ID,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: time.Now().UTC().Add(helpers.ExportJobDuration),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrExportInProgress):
+			helpers.HandleErrorResponse(ctx, http.StatusConflict, err)
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	link := dataExportLink(uh.app, export.ID, token)
+	go exportUserData(uh.app, export, user, link)
+
+	exportResponse := response.DataExportResponseFromModel(export)
+	exportResponse.DownloadURL = link
+	ctx.JSON(
+		http.StatusAccepted,
+		exportResponse,
+	)
+}
+
+// GetExport reports the progress of a data export of an authenticated user.
+func (uh userHandler) GetExport(ctx *gin.Context) {
+	user := helpers.ContextGetUser(ctx)
+	if reflect.DeepEqual(user, models.User{}) {
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("unauthenticated"))
+		return
+	}
+
+	exportID := ctx.Param("id")
+	if _, err := uuid.Parse(exportID); err != nil {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("export not found"))
+		return
+	}
+
+	export, err := uh.app.Repositories.DataExports.Get(exportID, user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("export not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		response.DataExportResponseFromModel(export),
+	)
+}
+
+// DownloadExport serves a completed data export to whoever holds its download link.
+// Each link can be used once, as it ends up in the access log.
+func (uh userHandler) DownloadExport(ctx *gin.Context) {
+	token := ctx.Param("token")
+	if token == "" {
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("export not found"))
+		return
+	}
+
+	export, err := uh.app.Repositories.DataExports.GetByTokenHash(helpers.HashToken(token))
+	if err != nil || export.ID != ctx.Param("id") {
+		switch {
+		case err == nil, errors.Is(err, repository.ErrRecordNotFound):
+			helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("export not found"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	switch {
+	case time.Now().After(export.ExpiresAt):
+		helpers.HandleErrorResponse(ctx, http.StatusGone, errors.New("export has expired"))
+		return
+	case export.Status == models.DataExportStatusPending:
+		helpers.HandleErrorResponse(ctx, http.StatusConflict, errors.New("export is not ready yet"))
+		return
+	case export.Status != models.DataExportStatusCompleted:
+		helpers.HandleErrorResponse(ctx, http.StatusNotFound, errors.New("export failed, please start a new one"))
+		return
+	}
+
+	// use up the link, this fails if it was used before
+	if err := uh.app.Repositories.DataExports.MarkDownloaded(export.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTokenReused):
+			helpers.HandleErrorResponse(ctx, http.StatusGone, errors.New("export was already downloaded, please start a new one"))
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+		return
+	}
+
+	ctx.FileAttachment(export.FilePath, fmt.Sprintf("memo-export-%s.zip", export.CompletedAt.Time.Format("2006-01-02")))
+}
+
+// dataExportLink returns the download link of a data export.
+func dataExportLink(app internal.Application, exportID string, token string) string {
+	return fmt.Sprintf("%s/users/export/%s/download/%s", strings.TrimSuffix(app.Config.AppURL, "/"), exportID, url.PathEscape(token))
+}
+
+// exportUserData builds the archive of a data export and mails its download link to the user.
+// It is meant to run in its own goroutine, so failures are recorded on the export and logged rather than returned.
+func exportUserData(app internal.Application, export models.DataExport, user models.User, link string) {
+	filePath := helpers.DataExportPath(app.Config.ExportDir, user.ID, export.ID)
+
+	// a panic here would take down the whole server
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic exporting data of user %s: %v\n%s", user.ID, r, debug.Stack())
+			if err := app.Repositories.DataExports.Fail(export.ID, "the export could not be completed"); err != nil {
+				log.Printf("error recording failed export %s: %s", export.ID, err.Error())
+			}
+			if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("error removing failed export %s: %s", export.ID, err.Error())
+			}
+		}
+	}()
+
+	documents, media, err := collectUserData(app, user.ID)
+	if err == nil {
+		err = helpers.WriteDataExport(filePath, documents, media)
+	}
+	if err != nil {
+		log.Printf("error exporting data of user %s: %s", user.ID, err.Error())
+		if err := app.Repositories.DataExports.Fail(export.ID, "the export could not be completed"); err != nil {
+			log.Printf("error recording failed export %s: %s", export.ID, err.Error())
+		}
+		return
+	}
+
+	// an export that took too long may have been given up on and removed in the meantime
+	if err := app.Repositories.DataExports.Complete(export.ID, filePath, time.Now().UTC().Add(helpers.ExportLinkDuration)); err != nil {
+		log.Printf("error recording completed export %s: %s", export.ID, err.Error())
+		if err := os.Remove(filePath); err != nil {
+			log.Printf("error removing abandoned export %s: %s", export.ID, err.Error())
+		}
+		return
+	}
+
+	if err := app.Repositories.Mailer.Send(helpers.DataExportEmail(user, link)); err != nil {
+		log.Printf("error sending data export email to user %s: %s", user.ID, err.Error())
+	}
+}
+
+// collectUserData gathers the documents and media making up the data export of a user.
+func collectUserData(app internal.Application, userID string) ([]helpers.ExportDocument, []helpers.ExportMedia, error) {
+	user, err := app.Repositories.Users.GetById(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memos, err := fetchAllPages(func(page, pageSize int) ([]models.Memo, error) {
+		return app.Repositories.Memo.GetMemosByOwnerID(userID, userID, page, pageSize)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	likes, err := fetchAllPages(func(page, pageSize int) ([]models.Like, error) {
+		return app.Repositories.Memo.GetLikesByUserID(userID, page, pageSize)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shares, err := fetchAllPages(func(page, pageSize int) ([]models.Share, error) {
+		return app.Repositories.Memo.GetSharesByUserID(userID, page, pageSize)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comments, err := fetchAllPages(func(page, pageSize int) ([]models.Comment, error) {
+		return app.Repositories.Social.GetCommentsByOwnerID(userID, page, pageSize)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	followers, err := fetchAllPages(func(page, pageSize int) ([]models.User, error) {
+		return app.Repositories.Users.GetFollowersOfUser(userID, page, pageSize)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	following, err := fetchAllPages(func(page, pageSize int) ([]models.User, error) {
+		return app.Repositories.Users.GetUsersFollowedBy(userID, page, pageSize)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	documents := []helpers.ExportDocument{
+		{Name: "profile.json", Data: response.UserResponseFromModel(user)},
+		{Name: "memos.json", Data: response.MultipleMemoResponseFromModel(memos)},
+		{Name: "likes.json", Data: response.MultipleExportLikeFromModel(likes)},
+		{Name: "shares.json", Data: response.MultipleExportShareFromModel(shares)},
+		{Name: "comments.json", Data: response.MultipleCommentResponseFromModel(comments)},
+		{Name: "followers.json", Data: response.MultipleExportFollowFromModel(followers)},
+		{Name: "following.json", Data: response.MultipleExportFollowFromModel(following)},
+	}
+
+	// media is uploaded under the ID of the user, memo or comment it belongs to
+	media := make([]helpers.ExportMedia, 0)
+	if user.AvatarURL != "" {
+		media = append(media, helpers.ExportMedia{Name: "media/avatar", URL: user.AvatarURL})
+	}
+	for _, memo := range memos {
+		if memo.MemoType != "text" && memo.Content != "" {
+			media = append(media, helpers.ExportMedia{Name: "media/memos/" + memo.ID, URL: memo.Content})
+		}
+	}
+	for _, comment := range comments {
+		if comment.CommentType != "text" && comment.Content != "" {
+			media = append(media, helpers.ExportMedia{Name: "media/comments/" + comment.ID, URL: comment.Content})
+		}
+	}
+
+	return documents, media, nil
+}
+
+// fetchAllPages calls fetch with increasing page numbers until a page comes back short, returning every item.
+func fetchAllPages[T any](fetch func(page, pageSize int) ([]T, error)) ([]T, error) {
+	items := make([]T, 0)
+	for page := 1; ; page++ {
+		pageItems, err := fetch(page, helpers.ExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		if len(pageItems) < helpers.ExportPageSize {
+			return items, nil
+		}
+	}
+}
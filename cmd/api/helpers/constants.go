@@ -7,10 +7,56 @@ var (
 	ReadTimeout  = 5 * time.Second
 	WriteTimeout = 10 * time.Second
 	MaxAge       = 12 * time.Hour
+
+	// SessionCacheDuration bounds how long a session revoked on another server instance stays usable
+	SessionCacheDuration = 30 * time.Second
+
+	// JWKSCacheDuration is how long clients may cache the published keys, so a new key should be
+	// added at least this long before it becomes the active key
+	JWKSCacheDuration = 5 * time.Minute
 )
 
 const (
-	DefaultPage         string = "1"
-	DefaultPageSize     string = "10"
-	AccessTokenDuration        = 365 * 24 * time.Hour
+	DefaultPage          string = "1"
+	DefaultPageSize      string = "10"
+	MaxSearchLength             = 100
+	AccessTokenDuration         = 24 * time.Hour
+	RefreshTokenDuration        = 7 * 24 * time.Hour
+
+	EmailVerificationTokenDuration = 48 * time.Hour
+	PasswordResetTokenDuration     = 1 * time.Hour
+	TwoFactorChallengeDuration     = 5 * time.Minute
+	RecoveryCodeCount              = 10
+
+	// TOTP secrets stored in plaintext are encrypted at startup TOTPSealBatchSize at a time
+	TOTPSealBatchSize = 100
+
+	// failed logins beyond a threshold lock the account or IP address out for
+	// LockoutBaseDuration, doubling with every further failure up to MaxLockoutDuration
+	AccountLockoutThreshold = 5
+	IPLockoutThreshold      = 20
+	LockoutBaseDuration     = 1 * time.Minute
+	MaxLockoutDuration      = 1 * time.Hour
+	LoginFailureWindow      = 24 * time.Hour
+
+	// deleted users past their grace period are looked for every PurgeInterval and purged PurgeBatchSize at a time
+	PurgeInterval  = 1 * time.Hour
+	PurgeBatchSize = 100
+
+	// exports not completed within ExportJobDuration are given up on,
+	// completed exports can be downloaded for ExportLinkDuration
+	ExportJobDuration  = 1 * time.Hour
+	ExportLinkDuration = 24 * time.Hour
+	ExportPageSize     = 100
+	ExportMediaTimeout = 2 * time.Minute
+	ExportMediaMaxSize = 100 << 20
+
+	// follow suggestions count users active within SuggestionActivityWindow towards popularity,
+	// and are cached for SuggestionCacheDuration
+	SuggestionLimit          = 20
+	SuggestionActivityWindow = 30 * 24 * time.Hour
+	SuggestionCacheDuration  = 10 * time.Minute
+
+	// hashtag search suggests up to HashtagSearchLimit hashtags
+	HashtagSearchLimit = 10
 )
@@ -8,7 +8,11 @@ import (
 
 type ContextKey string
 
-const UserContextKey = ContextKey("user")
+const (
+	UserContextKey    = ContextKey("user")
+	SessionContextKey = ContextKey("session")
+	ScopesContextKey  = ContextKey("scopes")
+)
 
 // ContextSetUser saves the given user data in the request context.
 func ContextSetUser(ctx *gin.Context, user models.User) {
@@ -23,3 +27,25 @@ func ContextGetUser(ctx *gin.Context) models.User {
 	}
 	return user
 }
+
+// ContextSetSessionID saves the ID of the session the request was authenticated with in the request context.
+func ContextSetSessionID(ctx *gin.Context, sessionID string) {
+	ctx.Set(string(SessionContextKey), sessionID)
+}
+
+// ContextGetSessionID returns the session ID stored in the request context.
+func ContextGetSessionID(ctx *gin.Context) string {
+	return ctx.GetString(string(SessionContextKey))
+}
+
+// ContextSetScopes saves the scopes of the personal access token the request was authenticated with in the request context.
+func ContextSetScopes(ctx *gin.Context, scopes []string) {
+	ctx.Set(string(ScopesContextKey), scopes)
+}
+
+// ContextGetScopes returns the scopes stored in the request context.
+// ok is false when the request was not authenticated with a personal access token and is therefore unrestricted.
+func ContextGetScopes(ctx *gin.Context) (scopes []string, ok bool) {
+	scopes, ok = ctx.Value(string(ScopesContextKey)).([]string)
+	return scopes, ok
+}
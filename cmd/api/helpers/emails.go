@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"fmt"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+// VerificationEmail returns the email asking a user to confirm their email address.
+func VerificationEmail(user models.User, link string) models.Email {
+	return models.Email{
+		To:      user.Email,
+		Subject: "Verify your MeMo email address",
+		Body: fmt.Sprintf(`Hi %s,
+
+Please confirm your email address by following the link below:
+
+%s
+
+The link expires in %d hours. If you did not create a MeMo account, you can ignore this email.
+`, user.FirstName, link, int(EmailVerificationTokenDuration.Hours())),
+	}
+}
+
+// PasswordResetEmail returns the email carrying a link to reset a user's password.
+func PasswordResetEmail(user models.User, link string) models.Email {
+	return models.Email{
+		To:      user.Email,
+		Subject: "Reset your MeMo password",
+		Body: fmt.Sprintf(`Hi %s,
+
+Someone asked to reset the password of your MeMo account. Follow the link below to choose a new one:
+
+%s
+
+The link expires in %d minutes and can only be used once. If you did not ask for this, you can ignore this email.
+`, user.FirstName, link, int(PasswordResetTokenDuration.Minutes())),
+	}
+}
+
+// DataExportEmail returns the email carrying a link to download a user's data export.
+func DataExportEmail(user models.User, link string) models.Email {
+	return models.Email{
+		To:      user.Email,
+		Subject: "Your MeMo data export is ready",
+		Body: fmt.Sprintf(`Hi %s,
+
+The export of your MeMo data you asked for is ready. Follow the link below to download it:
+
+%s
+
+The link can be used once and expires in %d hours. If you did not ask for this, please change your password.
+`, user.FirstName, link, int(ExportLinkDuration.Hours())),
+	}
+}
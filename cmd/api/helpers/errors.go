@@ -3,7 +3,10 @@ package helpers
 import (
 	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,3 +32,14 @@ func HandleValidationError(ctx *gin.Context, err error) {
 func HandleLogicalDeleteError(ctx *gin.Context, data interface{}, err error) {
 	ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error(), "data": data})
 }
+
+// HandleLockedOut writes a 429 response telling the client to retry once lockedUntil has passed.
+func HandleLockedOut(ctx *gin.Context, lockedUntil time.Time) {
+	retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	HandleErrorResponse(ctx, http.StatusTooManyRequests, errors.New("too many failed login attempts, try again later"))
+}
@@ -0,0 +1,155 @@
+package helpers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ExportDocument is a JSON file to include in a data export.
+type ExportDocument struct {
+	Name string
+	Data any
+}
+
+// ExportMedia is an uploaded file to include in a data export.
+// Name is its path within the archive, without an extension, as that is taken from the URL.
+type ExportMedia struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// unavailableMediaFile lists the media that could not be downloaded into an export, so that it is clear
+// from the archive itself what is missing and where it was last seen.
+const unavailableMediaFile = "media/unavailable.json"
+
+var exportClient = http.Client{Timeout: ExportMediaTimeout}
+
+// DataExportUserDir returns the directory the data exports of a user are written to within exportDir.
+// Keeping the exports of each user apart lets them all be removed when the user is purged.
+func DataExportUserDir(exportDir string, userID string) string {
+	return filepath.Join(exportDir, userID)
+}
+
+// DataExportPath returns where the archive of a data export is written within exportDir.
+func DataExportPath(exportDir string, userID string, exportID string) string {
+	return filepath.Join(DataExportUserDir(exportDir, userID), exportID+".zip")
+}
+
+// WriteDataExport writes a ZIP archive to filePath holding the documents as indented JSON files,
+// followed by the media that can still be downloaded.
+// The archive is written to a temporary file first, so filePath only ever holds a complete archive.
+func WriteDataExport(filePath string, documents []ExportDocument, media []ExportMedia) (err error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), ".export-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tempFile.Close()
+			_ = os.Remove(tempFile.Name())
+		}
+	}()
+
+	archive := zip.NewWriter(tempFile)
+	for _, document := range documents {
+		writer, err := archive.Create(document.Name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(document.Data); err != nil {
+			return err
+		}
+	}
+
+	unavailable := make([]ExportMedia, 0)
+	for _, file := range media {
+		if err := writeExportMedia(archive, file); err != nil {
+			unavailable = append(unavailable, file)
+		}
+	}
+	if len(unavailable) > 0 {
+		writer, err := archive.Create(unavailableMediaFile)
+		if err != nil {
+			return err
+		}
+		if err := json.NewEncoder(writer).Encode(unavailable); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), filePath)
+}
+
+// writeExportMedia downloads a file into the archive. Downloads larger than ExportMediaMaxSize are abandoned.
+// The download is buffered in a temporary file, so a failed download leaves no partial file in the archive.
+func writeExportMedia(archive *zip.Writer, file ExportMedia) error {
+	res, err := exportClient.Get(file.URL)
+	if err != nil {
+		return err
+	}
+	defer func(body io.ReadCloser) {
+		err := body.Close()
+		if err != nil {
+			return
+		}
+	}(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	if res.ContentLength > ExportMediaMaxSize {
+		return errors.New("media is too large")
+	}
+
+	buffer, err := os.CreateTemp("", "export-media-*")
+	if err != nil {
+		return err
+	}
+	defer func(buffer *os.File) {
+		_ = buffer.Close()
+		_ = os.Remove(buffer.Name())
+	}(buffer)
+
+	written, err := io.Copy(buffer, io.LimitReader(res.Body, ExportMediaMaxSize+1))
+	if err != nil {
+		return err
+	}
+	if written > ExportMediaMaxSize {
+		return errors.New("media is too large")
+	}
+	if _, err := buffer.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// media is stored rather than compressed, as images, audio and video are compressed already
+	writer, err := archive.CreateHeader(&zip.FileHeader{
+		Name:   file.Name + path.Ext(res.Request.URL.Path),
+		Method: zip.Store,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, buffer)
+	return err
+}
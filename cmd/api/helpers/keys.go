@@ -0,0 +1,307 @@
+package helpers
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// KeySet holds the key tokens are signed with and every key tokens are verified with.
+//
+// Asymmetric keys are loaded from a directory of PEM files, each named <kid>.pem.
+// Private keys can sign and verify, public keys can only verify.
+// To rotate keys:
+//  1. add the new private key to the directory while the active key ID still names the old key,
+//     which publishes the new key without signing with it;
+//  2. once JWKSCacheDuration has passed, make the new key active, either by setting the active key ID
+//     or by clearing it and naming the new key so that it sorts last;
+//  3. replace the old private key with its public key so that tokens it signed keep verifying;
+//  4. delete the old public key once RefreshTokenDuration has passed and every token it signed has expired.
+type KeySet struct {
+	method     jwt.SigningMethod
+	signingKID string
+	signingKey interface{}
+	verifiers  map[string]verifier
+
+	// legacyCutover is when signing with the legacy HS256 secret stopped
+	legacyCutover time.Time
+}
+
+type verifier struct {
+	method jwt.SigningMethod
+	key    interface{}
+	legacy bool
+}
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// NewHMACKeySet returns a key set which signs and verifies tokens with a shared secret.
+func NewHMACKeySet(secret string) *KeySet {
+	return &KeySet{
+		method:     jwt.SigningMethodHS256,
+		signingKey: []byte(secret),
+		verifiers: map[string]verifier{
+			"": {method: jwt.SigningMethodHS256, key: []byte(secret)},
+		},
+	}
+}
+
+// LoadKeySet reads the RS256 or EdDSA keys in dir.
+// The key named activeKID signs new tokens, or the private key whose name sorts last when activeKID is empty.
+// When legacySecret and legacyCutover are both set, HS256 tokens without a key ID issued before legacyCutover
+// keep verifying for up to RefreshTokenDuration after it, which allows moving off HS256 without signing every user out.
+func LoadKeySet(algorithm string, dir string, activeKID string, legacySecret string, legacyCutover time.Time) (*KeySet, error) {
+	var method jwt.SigningMethod
+	switch algorithm {
+	case AlgorithmRS256:
+		method = jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		method = SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key set algorithm %q", algorithm)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	keys := &KeySet{
+		method:        method,
+		verifiers:     make(map[string]verifier),
+		legacyCutover: legacyCutover,
+	}
+	if legacySecret != "" && !legacyCutover.IsZero() {
+		keys.verifiers[""] = verifier{method: jwt.SigningMethodHS256, key: []byte(legacySecret), legacy: true}
+	}
+
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+
+		privateKey, publicKey, err := readPEMKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading key %s: %w", path, err)
+		}
+		if !keyMatchesAlgorithm(publicKey, algorithm) {
+			return nil, fmt.Errorf("key %s cannot be used with %s", path, algorithm)
+		}
+
+		keys.verifiers[kid] = verifier{method: method, key: publicKey}
+		if privateKey != nil && (activeKID == "" || activeKID == kid) {
+			keys.signingKID = kid
+			keys.signingKey = privateKey
+		}
+	}
+
+	if keys.signingKey == nil {
+		if activeKID != "" {
+			return nil, fmt.Errorf("no private key with ID %q found in %s", activeKID, dir)
+		}
+		return nil, fmt.Errorf("no private key found in %s", dir)
+	}
+
+	return keys, nil
+}
+
+// readPEMKey parses a PEM encoded private or public key.
+// The public key is always returned, the private key only when the file holds one.
+func readPEMKey(path string) (privateKey interface{}, publicKey interface{}, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("no PEM data found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+		return nil, publicKey, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return key, &key.PublicKey, nil
+	case ed25519.PrivateKey:
+		return key, key.Public(), nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+}
+
+func keyMatchesAlgorithm(publicKey interface{}, algorithm string) bool {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return algorithm == AlgorithmRS256
+	case ed25519.PublicKey:
+		return algorithm == AlgorithmEdDSA
+	default:
+		return false
+	}
+}
+
+// Sign signs the claims with the active key, naming it in the kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.signingKID != "" {
+		token.Header["kid"] = k.signingKID
+	}
+	return token.SignedString(k.signingKey)
+}
+
+// Parse verifies a signed token with the key named in its kid header and decodes its claims.
+// The token must use the algorithm that key was loaded for.
+func (k *KeySet) Parse(signedToken string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(signedToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		verifier, ok := k.verifiers[kid]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		if token.Method.Alg() != verifier.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		if verifier.legacy {
+			if err := k.checkLegacyToken(token); err != nil {
+				return nil, err
+			}
+		}
+		return verifier.key, nil
+	})
+}
+
+// checkLegacyToken rejects tokens signed with the legacy HS256 secret after the cutover,
+// and every such token once the longest lived token issued before the cutover has expired.
+// Anyone holding the secret can forge the issue time, so the second check is what bounds the exposure.
+func (k *KeySet) checkLegacyToken(token *jwt.Token) error {
+	claims, ok := token.Claims.(*Claims)
+	if !ok {
+		return errors.New("unexpected claims")
+	}
+	if time.Now().After(k.legacyCutover.Add(RefreshTokenDuration)) || claims.IssuedAt > k.legacyCutover.Unix() {
+		return errors.New("legacy signing key no longer accepted")
+	}
+
+	log.Printf("accepted a token signed with the legacy HS256 secret for user %s, accepted until %s\n",
+		claims.Subject, k.legacyCutover.Add(RefreshTokenDuration).Format(time.RFC3339))
+	return nil
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS returns the public keys other services can verify tokens with.
+// It is empty for HS256, where the shared secret must never be published.
+func (k *KeySet) JWKS() []JWK {
+	kids := make([]string, 0, len(k.verifiers))
+	for kid := range k.verifiers {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := make([]JWK, 0, len(kids))
+	for _, kid := range kids {
+		switch key := k.verifiers[kid].key.(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, JWK{
+				KeyType:   "RSA",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: AlgorithmRS256,
+				N:         base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, JWK{
+				KeyType:   "OKP",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: AlgorithmEdDSA,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(key),
+			})
+		}
+	}
+	return jwks
+}
+
+// SigningMethodEdDSA implements Ed25519 signatures, which jwt-go does not provide.
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+type signingMethodEdDSA struct{}
+
+func init() {
+	jwt.RegisterSigningMethod(AlgorithmEdDSA, func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return AlgorithmEdDSA
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return errors.New("ed25519: verification error")
+	}
+	return nil
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
@@ -0,0 +1,208 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const testLegacySecret = "legacy-secret"
+
+// generateTestKey returns a new private key for the algorithm along with its public key.
+func generateTestKey(t *testing.T, algorithm string) (crypto.Signer, crypto.PublicKey) {
+	t.Helper()
+
+	switch algorithm {
+	case AlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key, &key.PublicKey
+	case AlgorithmEdDSA:
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return privateKey, publicKey
+	default:
+		t.Fatalf("unsupported algorithm %q", algorithm)
+		return nil, nil
+	}
+}
+
+// writeTestKey writes a PEM encoded private or public key to dir as <kid>.pem.
+func writeTestKey(t *testing.T, dir string, kid string, key interface{}) {
+	t.Helper()
+
+	var block *pem.Block
+	switch key.(type) {
+	case *rsa.PrivateKey, ed25519.PrivateKey:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	default:
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testClaims(issuedAt time.Time) Claims {
+	return Claims{
+		Type: TokenTypeAccess,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   "user",
+			IssuedAt:  issuedAt.Unix(),
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+}
+
+// signTestToken signs claims with key, naming kid in the header whether or not key belongs to it.
+func signTestToken(t *testing.T, algorithm string, kid string, key interface{}) string {
+	t.Helper()
+
+	method := jwt.GetSigningMethod(algorithm)
+	token := jwt.NewWithClaims(method, testClaims(time.Now()))
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signedToken, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signedToken
+}
+
+func TestKeySetVerifiesByKeyID(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			// the old key has been rotated out and only its public key is left
+			oldPrivateKey, oldPublicKey := generateTestKey(t, algorithm)
+			newPrivateKey, _ := generateTestKey(t, algorithm)
+			strangerKey, _ := generateTestKey(t, algorithm)
+
+			dir := t.TempDir()
+			writeTestKey(t, dir, "2024-01", oldPublicKey)
+			writeTestKey(t, dir, "2024-02", newPrivateKey)
+
+			keys, err := LoadKeySet(algorithm, dir, "", "", time.Time{})
+			if err != nil {
+				t.Fatalf("LoadKeySet: %s", err)
+			}
+
+			signedByKeySet, err := keys.Sign(testClaims(time.Now()))
+			if err != nil {
+				t.Fatalf("Sign: %s", err)
+			}
+
+			tests := []struct {
+				name        string
+				signedToken string
+				valid       bool
+			}{
+				{"signed by the key set", signedByKeySet, true},
+				{"signed by the active key", signTestToken(t, algorithm, "2024-02", newPrivateKey), true},
+				{"signed by a rotated out key", signTestToken(t, algorithm, "2024-01", oldPrivateKey), true},
+				{"signed by another key than named", signTestToken(t, algorithm, "2024-02", oldPrivateKey), false},
+				{"signed by an unknown key", signTestToken(t, algorithm, "2024-03", strangerKey), false},
+				{"signed without a key ID", signTestToken(t, algorithm, "", newPrivateKey), false},
+				{"signed with the legacy secret", signTestToken(t, AlgorithmHS256, "", []byte(testLegacySecret)), false},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					_, err := keys.Parse(tt.signedToken, &Claims{})
+					if tt.valid && err != nil {
+						t.Errorf("expected the token to verify, got %s", err)
+					}
+					if !tt.valid && err == nil {
+						t.Error("expected the token to be rejected")
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestKeySetRejectsAlgorithmMismatch(t *testing.T) {
+	rsaKey, _ := generateTestKey(t, AlgorithmRS256)
+	edKey, _ := generateTestKey(t, AlgorithmEdDSA)
+
+	dir := t.TempDir()
+	writeTestKey(t, dir, "rsa", rsaKey)
+	if _, err := LoadKeySet(AlgorithmEdDSA, dir, "", "", time.Time{}); err == nil {
+		t.Error("expected an RSA key to be refused for EdDSA")
+	}
+
+	keys, err := LoadKeySet(AlgorithmRS256, dir, "", "", time.Time{})
+	if err != nil {
+		t.Fatalf("LoadKeySet: %s", err)
+	}
+	if _, err := keys.Parse(signTestToken(t, AlgorithmEdDSA, "rsa", edKey), &Claims{}); err == nil {
+		t.Error("expected an EdDSA token naming an RSA key to be rejected")
+	}
+}
+
+func TestKeySetLegacyHS256Tokens(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		secret   string
+		cutover  time.Time
+		issuedAt time.Time
+		valid    bool
+	}{
+		{"issued before the cutover", testLegacySecret, now.Add(-time.Hour), now.Add(-2 * time.Hour), true},
+		{"issued after the cutover", testLegacySecret, now.Add(-time.Hour), now.Add(-time.Minute), false},
+		{"cutover in the future", testLegacySecret, now.Add(time.Hour), now, true},
+		{"every token issued before the cutover expired", testLegacySecret, now.Add(-RefreshTokenDuration - time.Minute), now.Add(-RefreshTokenDuration - time.Hour), false},
+		{"no cutover set", testLegacySecret, time.Time{}, now.Add(-time.Hour), false},
+		{"no secret set", "", now.Add(-time.Hour), now.Add(-2 * time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, _ := generateTestKey(t, AlgorithmEdDSA)
+			dir := t.TempDir()
+			writeTestKey(t, dir, "active", privateKey)
+
+			keys, err := LoadKeySet(AlgorithmEdDSA, dir, "", tt.secret, tt.cutover)
+			if err != nil {
+				t.Fatalf("LoadKeySet: %s", err)
+			}
+
+			signedToken, err := NewHMACKeySet(testLegacySecret).Sign(testClaims(tt.issuedAt))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = keys.Parse(signedToken, &Claims{})
+			if tt.valid && err != nil {
+				t.Errorf("expected the token to verify, got %s", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected the token to be rejected")
+			}
+		})
+	}
+}
@@ -1,29 +1,173 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var ErrUnknownPasswordHash = errors.New("unknown password hash format")
+
+// PasswordHasher hashes passwords with the configured algorithm and parameters.
+// Hashes are stored in a self-describing format, bcrypt's own for bcrypt and the PHC string format
+// ($argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>) for argon2id,
+// so hashes made with other algorithms or parameters can still be verified and recognised as outdated.
+type PasswordHasher struct {
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+
+	// dummyHash is verified against when there is no real hash to check,
+	// so that unknown accounts take as long to reject as wrong passwords
+	dummyHash string
+}
+
+// Argon2Params are the tunable argon2id parameters. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// NewPasswordHasher returns a PasswordHasher using the given algorithm and parameters.
+func NewPasswordHasher(algorithm string, bcryptCost int, argon2Params Argon2Params) (PasswordHasher, error) {
+	hasher := PasswordHasher{
+		Algorithm:  algorithm,
+		BcryptCost: bcryptCost,
+		Argon2:     argon2Params,
+	}
+
+	dummyPassword, err := GenerateOpaqueToken()
+	if err != nil {
+		return PasswordHasher{}, err
+	}
+	if err := hasher.HashPassword(&dummyPassword); err != nil {
+		return PasswordHasher{}, err
+	}
+	hasher.dummyHash = dummyPassword
+
+	return hasher, nil
+}
+
 //HashPassword is used to encrypt the password before it is stored in the repository.
-func HashPassword(password *string) error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(*password), 14)
+func (h PasswordHasher) HashPassword(password *string) error {
+	var hash string
+	var err error
+	switch h.Algorithm {
+	case PasswordAlgorithmArgon2id:
+		hash, err = h.hashArgon2id(*password)
+	default:
+		var hashBytes []byte
+		hashBytes, err = bcrypt.GenerateFromPassword([]byte(*password), h.BcryptCost)
+		hash = string(hashBytes)
+	}
 	if err != nil {
 		return err
 	}
 
-	*password = string(hash)
+	*password = hash
 	return nil
 }
 
 //VerifyPassword checks the inputted plaintext password against the password hash in the repository.
-func VerifyPassword(hashedPassword, plainPassword string) (bool, error) {
+// rehash is set when the password matches but the hash was made with another algorithm or other parameters
+// than the hasher is configured with, in which case the password should be hashed again and stored.
+func (h PasswordHasher) VerifyPassword(hashedPassword, plainPassword string) (valid bool, rehash bool, err error) {
+	if strings.HasPrefix(hashedPassword, "$"+PasswordAlgorithmArgon2id+"$") {
+		params, salt, key, err := decodeArgon2id(hashedPassword)
+		if err != nil {
+			return false, false, err
+		}
+
+		computed := argon2.IDKey([]byte(plainPassword), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(computed, key) != 1 {
+			return false, false, nil
+		}
+		return true, h.Algorithm != PasswordAlgorithmArgon2id || params != h.Argon2, nil
+	}
+
 	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword)); err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return false, nil
+			return false, false, nil
 		}
-		return false, err
+		return false, false, err
+	}
+
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return false, false, err
+	}
+	return true, h.Algorithm != PasswordAlgorithmBcrypt || cost != h.BcryptCost, nil
+}
+
+func (h PasswordHasher) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Argon2.Iterations, h.Argon2.Memory, h.Argon2.Parallelism, argon2KeyLength)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.Argon2.Memory,
+		h.Argon2.Iterations,
+		h.Argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// decodeArgon2id parses an argon2id hash in the PHC string format.
+func decodeArgon2id(encodedHash string) (params Argon2Params, salt []byte, key []byte, err error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	return params, salt, key, nil
+}
+
+// VerifyNoPassword does the work of VerifyPassword without a hash to check against, for when the account
+// being logged into does not exist. Rejecting unknown accounts as slowly as wrong passwords keeps callers
+// from telling the two apart.
+func (h PasswordHasher) VerifyNoPassword(plainPassword string) {
+	if h.dummyHash == "" {
+		return
 	}
-	return true, nil
+	_, _, _ = h.VerifyPassword(h.dummyHash, plainPassword)
 }
@@ -0,0 +1,189 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheap parameters keep the tests fast, the hashes behave the same at any cost
+var testArgon2Params = Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func newTestPasswordHasher(t *testing.T, algorithm string, bcryptCost int, argon2Params Argon2Params) PasswordHasher {
+	t.Helper()
+
+	hasher, err := NewPasswordHasher(algorithm, bcryptCost, argon2Params)
+	if err != nil {
+		t.Fatalf("NewPasswordHasher: %s", err)
+	}
+	return hasher
+}
+
+func hashTestPassword(t *testing.T, hasher PasswordHasher, password string) string {
+	t.Helper()
+
+	hash := password
+	if err := hasher.HashPassword(&hash); err != nil {
+		t.Fatalf("HashPassword: %s", err)
+	}
+	return hash
+}
+
+func TestArgon2idEncodeDecode(t *testing.T) {
+	hasher := newTestPasswordHasher(t, PasswordAlgorithmArgon2id, bcrypt.MinCost, testArgon2Params)
+	hash := hashTestPassword(t, hasher, "correct horse")
+
+	if prefix := "$argon2id$v=19$m=1024,t=1,p=1$"; !strings.HasPrefix(hash, prefix) {
+		t.Fatalf("HashPassword = %q, want the %q prefix", hash, prefix)
+	}
+
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		t.Fatalf("decodeArgon2id(%q): %s", hash, err)
+	}
+	if params != testArgon2Params {
+		t.Errorf("decoded parameters %+v, want %+v", params, testArgon2Params)
+	}
+	if len(salt) != argon2SaltLength {
+		t.Errorf("decoded a %d byte salt, want %d bytes", len(salt), argon2SaltLength)
+	}
+	if len(key) != argon2KeyLength {
+		t.Errorf("decoded a %d byte key, want %d bytes", len(key), argon2KeyLength)
+	}
+
+	// every hash gets its own salt
+	_, otherSalt, _, err := decodeArgon2id(hashTestPassword(t, hasher, "correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(salt, otherSalt) {
+		t.Error("expected hashing the same password twice to use different salts")
+	}
+}
+
+func TestDecodeArgon2idRejectsMalformedHashes(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"too few parts", "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA"},
+		{"other version", "$argon2id$v=16$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5"},
+		{"malformed parameters", "$argon2id$v=19$m=1024$c2FsdHNhbHRzYWx0c2FsdA$a2V5"},
+		{"malformed salt", "$argon2id$v=19$m=1024,t=1,p=1$not base64!$a2V5"},
+		{"malformed key", "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$not base64!"},
+		{"empty key", "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := decodeArgon2id(tt.hash); !errors.Is(err, ErrUnknownPasswordHash) {
+				t.Errorf("decodeArgon2id(%q) = %v, want %v", tt.hash, err, ErrUnknownPasswordHash)
+			}
+		})
+	}
+}
+
+func TestVerifyPassword(t *testing.T) {
+	for _, algorithm := range []string{PasswordAlgorithmBcrypt, PasswordAlgorithmArgon2id} {
+		t.Run(algorithm, func(t *testing.T) {
+			hasher := newTestPasswordHasher(t, algorithm, bcrypt.MinCost, testArgon2Params)
+			hash := hashTestPassword(t, hasher, "correct horse")
+
+			tests := []struct {
+				password string
+				valid    bool
+			}{
+				{"correct horse", true},
+				{"correct horse ", false},
+				{"Correct horse", false},
+				{"", false},
+			}
+
+			for _, tt := range tests {
+				valid, rehash, err := hasher.VerifyPassword(hash, tt.password)
+				if err != nil {
+					t.Fatalf("VerifyPassword(%q): %s", tt.password, err)
+				}
+				if valid != tt.valid {
+					t.Errorf("VerifyPassword(%q) valid = %v, want %v", tt.password, valid, tt.valid)
+				}
+				if rehash {
+					t.Errorf("VerifyPassword(%q) asked to rehash a hash made with the configured parameters", tt.password)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyPasswordRehash(t *testing.T) {
+	strongerArgon2Params := Argon2Params{Memory: 2048, Iterations: 1, Parallelism: 1}
+
+	tests := []struct {
+		name       string
+		hashedWith PasswordHasher
+		verifiedBy PasswordHasher
+		rehash     bool
+	}{
+		{
+			"same bcrypt cost",
+			newTestPasswordHasher(t, PasswordAlgorithmBcrypt, bcrypt.MinCost, testArgon2Params),
+			newTestPasswordHasher(t, PasswordAlgorithmBcrypt, bcrypt.MinCost, testArgon2Params),
+			false,
+		},
+		{
+			"other bcrypt cost",
+			newTestPasswordHasher(t, PasswordAlgorithmBcrypt, bcrypt.MinCost, testArgon2Params),
+			newTestPasswordHasher(t, PasswordAlgorithmBcrypt, bcrypt.MinCost+1, testArgon2Params),
+			true,
+		},
+		{
+			"same argon2id parameters",
+			newTestPasswordHasher(t, PasswordAlgorithmArgon2id, bcrypt.MinCost, testArgon2Params),
+			newTestPasswordHasher(t, PasswordAlgorithmArgon2id, bcrypt.MinCost, testArgon2Params),
+			false,
+		},
+		{
+			"other argon2id parameters",
+			newTestPasswordHasher(t, PasswordAlgorithmArgon2id, bcrypt.MinCost, testArgon2Params),
+			newTestPasswordHasher(t, PasswordAlgorithmArgon2id, bcrypt.MinCost, strongerArgon2Params),
+			true,
+		},
+		{
+			"bcrypt to argon2id",
+			newTestPasswordHasher(t, PasswordAlgorithmBcrypt, bcrypt.MinCost, testArgon2Params),
+			newTestPasswordHasher(t, PasswordAlgorithmArgon2id, bcrypt.MinCost, testArgon2Params),
+			true,
+		},
+		{
+			"argon2id to bcrypt",
+			newTestPasswordHasher(t, PasswordAlgorithmArgon2id, bcrypt.MinCost, testArgon2Params),
+			newTestPasswordHasher(t, PasswordAlgorithmBcrypt, bcrypt.MinCost, testArgon2Params),
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := hashTestPassword(t, tt.hashedWith, "correct horse")
+
+			valid, rehash, err := tt.verifiedBy.VerifyPassword(hash, "correct horse")
+			if err != nil {
+				t.Fatalf("VerifyPassword: %s", err)
+			}
+			if !valid {
+				t.Fatal("expected the password to match a hash made with other parameters")
+			}
+			if rehash != tt.rehash {
+				t.Errorf("rehash = %v, want %v", rehash, tt.rehash)
+			}
+
+			// wrong passwords never ask for a rehash
+			if _, rehash, _ := tt.verifiedBy.VerifyPassword(hash, "wrong horse"); rehash {
+				t.Error("expected no rehash for a wrong password")
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package helpers
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+// permissions granted through roles, see the roles migration for which role holds which permission
+const (
+	PermissionUsersRead    = "users:read"
+	PermissionUsersSuspend = "users:suspend"
+	PermissionMemosRead    = "memos:read"
+	PermissionMemosSuspend = "memos:suspend"
+	PermissionRolesManage  = "roles:manage"
+	PermissionLockoutsRead = "lockouts:read"
+)
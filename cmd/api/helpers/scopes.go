@@ -0,0 +1,30 @@
+package helpers
+
+import "strings"
+
+// PersonalAccessTokenPrefix marks personal access tokens, telling them apart from JWTs at a glance.
+const PersonalAccessTokenPrefix = "memo_pat_"
+
+const (
+	ScopeMemoRead     = "memo:read"
+	ScopeMemoWrite    = "memo:write"
+	ScopeSocialRead   = "social:read"
+	ScopeSocialWrite  = "social:write"
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+)
+
+// Scopes lists every scope a personal access token may be granted.
+var Scopes = []string{
+	ScopeMemoRead,
+	ScopeMemoWrite,
+	ScopeSocialRead,
+	ScopeSocialWrite,
+	ScopeProfileRead,
+	ScopeProfileWrite,
+}
+
+// IsPersonalAccessToken reports whether a bearer token is a personal access token rather than a JWT.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
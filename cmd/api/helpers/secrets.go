@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// SealedSecretPrefix marks secrets sealed by a SecretBox, telling them apart from secrets stored before encryption.
+const SealedSecretPrefix = "v1:"
+
+// SecretBox encrypts secrets the server has to read back, such as TOTP secrets, with AES-256-GCM,
+// so that they are useless to anyone who can read the database but not the key.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox returns a SecretBox using the base64 encoded 32 byte key.
+func NewSecretBox(key string) (*SecretBox, error) {
+	rawKey, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("secret key must be base64 encoded")
+	}
+	if len(rawKey) != 32 {
+		return nil, errors.New("secret key must be 32 bytes long")
+	}
+
+	block, err := aes.NewCipher(rawKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal encrypts a secret with a random nonce.
+func (b *SecretBox) Seal(secret string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := b.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return SealedSecretPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a secret sealed by Seal. Secrets stored before encryption are returned as they are.
+func (b *SecretBox) Open(sealed string) (string, error) {
+	if !IsSealed(sealed) {
+		return sealed, nil
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, SealedSecretPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(data) < b.aead.NonceSize() {
+		return "", errors.New("sealed secret is too short")
+	}
+
+	nonce, ciphertext := data[:b.aead.NonceSize()], data[b.aead.NonceSize():]
+	secret, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// IsSealed reports whether a stored secret was sealed by a SecretBox.
+func IsSealed(secret string) bool {
+	return strings.HasPrefix(secret, SealedSecretPrefix)
+}
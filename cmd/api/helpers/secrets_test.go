@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func newTestSecretBox(t *testing.T, fill byte) *SecretBox {
+	t.Helper()
+
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), 32)))
+	box, err := NewSecretBox(key)
+	if err != nil {
+		t.Fatalf("NewSecretBox: %s", err)
+	}
+	return box
+}
+
+func TestNewSecretBoxRejectsInvalidKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"not base64", "not a key!"},
+		{"too short", base64.StdEncoding.EncodeToString(make([]byte, 16))},
+		{"too long", base64.StdEncoding.EncodeToString(make([]byte, 64))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSecretBox(tt.key); err == nil {
+				t.Error("expected the key to be rejected")
+			}
+		})
+	}
+}
+
+func TestSecretBoxRoundTrip(t *testing.T) {
+	box := newTestSecretBox(t, 'k')
+
+	for _, secret := range []string{"", rfc6238Secret, "a secret with spaces and ünïcode"} {
+		sealed, err := box.Seal(secret)
+		if err != nil {
+			t.Fatalf("Seal(%q): %s", secret, err)
+		}
+		if !IsSealed(sealed) {
+			t.Errorf("Seal(%q) = %q, expected the %q prefix", secret, sealed, SealedSecretPrefix)
+		}
+		if secret != "" && strings.Contains(sealed, secret) {
+			t.Errorf("Seal(%q) = %q, expected the secret not to show", secret, sealed)
+		}
+
+		opened, err := box.Open(sealed)
+		if err != nil {
+			t.Fatalf("Open(%q): %s", sealed, err)
+		}
+		if opened != secret {
+			t.Errorf("Open(Seal(%q)) = %q", secret, opened)
+		}
+	}
+}
+
+func TestSecretBoxSealsWithFreshNonces(t *testing.T) {
+	box := newTestSecretBox(t, 'k')
+
+	first, err := box.Seal(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := box.Seal(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Error("expected sealing the same secret twice to give different results")
+	}
+}
+
+func TestSecretBoxOpenPassesUnsealedSecretsThrough(t *testing.T) {
+	box := newTestSecretBox(t, 'k')
+
+	opened, err := box.Open(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened != rfc6238Secret {
+		t.Errorf("Open(%q) = %q, expected secrets stored before encryption to be returned as they are", rfc6238Secret, opened)
+	}
+}
+
+func TestSecretBoxOpenRejectsTamperedSecrets(t *testing.T) {
+	box := newTestSecretBox(t, 'k')
+
+	sealed, err := box.Seal(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, SealedSecretPrefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flipped := make([]byte, len(data))
+	copy(flipped, data)
+	flipped[len(flipped)-1] ^= 0x01
+
+	tests := []struct {
+		name   string
+		sealed string
+	}{
+		{"flipped bit", SealedSecretPrefix + base64.RawStdEncoding.EncodeToString(flipped)},
+		{"truncated", SealedSecretPrefix + base64.RawStdEncoding.EncodeToString(data[:len(data)-1])},
+		{"shorter than a nonce", SealedSecretPrefix + base64.RawStdEncoding.EncodeToString(data[:4])},
+		{"not base64", SealedSecretPrefix + "not base64!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := box.Open(tt.sealed); err == nil {
+				t.Error("expected the sealed secret to be rejected")
+			}
+		})
+	}
+}
+
+func TestSecretBoxOpenRejectsWrongKey(t *testing.T) {
+	sealed, err := newTestSecretBox(t, 'k').Seal(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newTestSecretBox(t, 'x').Open(sealed); err == nil {
+		t.Error("expected a secret sealed with another key to be rejected")
+	}
+}
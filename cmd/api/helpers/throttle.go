@@ -0,0 +1,25 @@
+package helpers
+
+import "time"
+
+const (
+	LoginScopeAccount = "account"
+	LoginScopeIP      = "ip"
+)
+
+// LockoutDuration returns how long failures consecutive failed logins lock a subject out for,
+// or zero while failures is below threshold.
+func LockoutDuration(failures int, threshold int) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+
+	duration := LockoutBaseDuration
+	for i := threshold; i < failures && duration < MaxLockoutDuration; i++ {
+		duration *= 2
+	}
+	if duration > MaxLockoutDuration {
+		duration = MaxLockoutDuration
+	}
+	return duration
+}
@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	const threshold = 5
+
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{"no failures", 0, 0},
+		{"below the threshold", threshold - 1, 0},
+		{"at the threshold", threshold, LockoutBaseDuration},
+		{"one past the threshold", threshold + 1, 2 * LockoutBaseDuration},
+		{"two past the threshold", threshold + 2, 4 * LockoutBaseDuration},
+		{"five past the threshold", threshold + 5, 32 * LockoutBaseDuration},
+		{"doubling past the cap", threshold + 6, MaxLockoutDuration},
+		{"far past the threshold", threshold + 1000, MaxLockoutDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LockoutDuration(tt.failures, threshold); got != tt.want {
+				t.Errorf("LockoutDuration(%d, %d) = %s, want %s", tt.failures, threshold, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutDurationNeverExceedsCap(t *testing.T) {
+	for _, threshold := range []int{AccountLockoutThreshold, IPLockoutThreshold} {
+		previous := time.Duration(0)
+		for failures := 0; failures < threshold+100; failures++ {
+			got := LockoutDuration(failures, threshold)
+			if got > MaxLockoutDuration {
+				t.Fatalf("LockoutDuration(%d, %d) = %s, exceeds %s", failures, threshold, got, MaxLockoutDuration)
+			}
+			if got < previous {
+				t.Fatalf("LockoutDuration(%d, %d) = %s, shorter than %s for one failure less", failures, threshold, got, previous)
+			}
+			previous = got
+		}
+	}
+}
@@ -1,59 +1,156 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
-// GenerateTokens generates both the detailed token and refresh token
-func GenerateTokens(jwtSecret string, userID string) (signedAccessToken string, signedRefreshToken string, err error) {
-	// generate access token with a lifetime of 24 hours
-	accessClaims := jwt.StandardClaims{
-		Subject:   userID,
-		ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
+const (
+	TokenTypeAccess            = "access"
+	TokenTypeRefresh           = "refresh"
+	TokenTypeEmailVerification = "email_verification"
+	TokenTypeTwoFactor         = "2fa_challenge"
+)
+
+// Claims are the JWT claims used for both access and refresh tokens.
+// Type distinguishes the two so that one cannot be used in place of the other.
+type Claims struct {
+	Type      string `json:"typ"`
+	SessionID string `json:"sid,omitempty"`
+	Email     string `json:"email,omitempty"`
+	jwt.StandardClaims
+}
+
+// TokenPair holds a freshly signed access and refresh token along with their expiry times.
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+}
+
+// GenerateTokens generates both the access token and refresh token for a session
+func GenerateTokens(keys *KeySet, userID string, sessionID string) (TokenPair, error) {
+	now := time.Now()
+	pair := TokenPair{
+		AccessExpiresAt:  now.Add(AccessTokenDuration),
+		RefreshExpiresAt: now.Add(RefreshTokenDuration),
+	}
+
+	accessClaims := Claims{
+		Type:      TokenTypeAccess,
+		SessionID: sessionID,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			Subject:   userID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: pair.AccessExpiresAt.Unix(),
+		},
 	}
 
-	// generate refresh token with a lifetime of 1 week
-	refreshClaims := jwt.StandardClaims{
-		Subject:   userID,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(),
+	// the random ID keeps refresh tokens unique, so each one hashes to a distinct value
+	refreshClaims := Claims{
+		Type:      TokenTypeRefresh,
+		SessionID: sessionID,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			Subject:   userID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: pair.RefreshExpiresAt.Unix(),
+		},
 	}
 
-	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(jwtSecret))
+	var err error
+	pair.AccessToken, err = keys.Sign(accessClaims)
 	if err != nil {
-		return "", "", err
+		return TokenPair{}, err
 	}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(jwtSecret))
+	pair.RefreshToken, err = keys.Sign(refreshClaims)
 	if err != nil {
-		return "", "", err
+		return TokenPair{}, err
+	}
+
+	return pair, nil
+}
+
+// GenerateEmailVerificationToken generates a token confirming that the user owns the email address.
+// The token is bound to the address, so it stops working once the address is verified or changed.
+func GenerateEmailVerificationToken(keys *KeySet, userID string, email string) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Type:  TokenTypeEmailVerification,
+		Email: email,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			Subject:   userID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(EmailVerificationTokenDuration).Unix(),
+		},
 	}
 
-	return accessToken, refreshToken, err
+	return keys.Sign(claims)
+}
+
+// GenerateTwoFactorChallengeToken generates a short-lived token proving that a user has passed the password check.
+// It is exchanged for a token pair once the second factor is verified.
+func GenerateTwoFactorChallengeToken(keys *KeySet, userID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(TwoFactorChallengeDuration)
+	claims := Claims{
+		Type: TokenTypeTwoFactor,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			Subject:   userID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+
+	signedToken, err := keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signedToken, expiresAt, nil
 }
 
 // ValidateToken validates the provided JWT.
-// An error is returned if the token is invalid or expired.
-func ValidateToken(jwtSecret string, signedToken string) (*jwt.StandardClaims, error) {
+// An error is returned if the token is invalid, expired or not of the expected type.
+func ValidateToken(keys *KeySet, signedToken string, tokenType string) (*Claims, error) {
 	// attempt to parse token
-	token, err := jwt.ParseWithClaims(
-		signedToken,
-		&jwt.StandardClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		},
-	)
+	token, err := keys.Parse(signedToken, &Claims{})
 	if err != nil {
 		return nil, errors.New("invalid or expired token")
 	}
 
 	// extract claims from token
-	claims, ok := token.Claims.(*jwt.StandardClaims)
-	if !ok {
+	claims, ok := token.Claims.(*Claims)
+	if !ok || claims.Type != tokenType {
 		return nil, errors.New("invalid or expired token")
 	}
 
 	return claims, nil
 }
+
+// GenerateOpaqueToken returns a random, URL safe token carrying no claims.
+func GenerateOpaqueToken() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// HashToken returns the hex encoded SHA-256 digest of a token.
+// Only this digest is stored, so a leaked database cannot be used to mint sessions.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
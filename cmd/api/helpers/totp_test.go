@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors, "12345678901234567890" in base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTPRFC6238Vectors(t *testing.T) {
+	// the RFC lists 8 digit codes, 6 digit codes are their last 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		step, valid := ValidateTOTP(rfc6238Secret, tt.code, time.Unix(tt.unix, 0))
+		if !valid {
+			t.Errorf("ValidateTOTP(%q) at %d: expected the code to be valid", tt.code, tt.unix)
+			continue
+		}
+		if want := tt.unix / totpPeriod; step != want {
+			t.Errorf("ValidateTOTP(%q) at %d: got step %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPDriftWindow(t *testing.T) {
+	// 081804 is the code of the period starting at 1111111080
+	const code = "081804"
+	issued := time.Unix(1111111109, 0)
+
+	tests := []struct {
+		name  string
+		drift time.Duration
+		valid bool
+	}{
+		{"same period", 0, true},
+		{"one period late", totpPeriod * time.Second, true},
+		{"one period early", -totpPeriod * time.Second, true},
+		{"two periods late", 2 * totpPeriod * time.Second, false},
+		{"two periods early", -2 * totpPeriod * time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, valid := ValidateTOTP(rfc6238Secret, code, issued.Add(tt.drift))
+			if valid != tt.valid {
+				t.Fatalf("got valid %v, want %v", valid, tt.valid)
+			}
+			if valid && step != issued.Unix()/totpPeriod {
+				t.Errorf("got step %d, want the step the code was issued in, %d", step, issued.Unix()/totpPeriod)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+	}{
+		{"short code", rfc6238Secret, "28708"},
+		{"long code", rfc6238Secret, "2870820"},
+		{"wrong code", rfc6238Secret, "287083"},
+		{"invalid secret", "not base32!", "287082"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, valid := ValidateTOTP(tt.secret, tt.code, now); valid {
+				t.Errorf("ValidateTOTP(%q, %q): expected the code to be rejected", tt.secret, tt.code)
+			}
+		})
+	}
+}
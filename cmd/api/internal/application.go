@@ -1,9 +1,30 @@
 package internal
 
-import "github.com/akinolaemmanuel49/memo-api/domain/repository"
+import (
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/cache"
+)
 
 // Application is a container for data required at different points throughout the server.
 type Application struct {
 	Config       Config
 	Repositories repository.Repositories
+
+	// Keys signs and verifies every token the server issues
+	Keys *helpers.KeySet
+
+	// Passwords hashes and verifies user passwords
+	Passwords helpers.PasswordHasher
+
+	// Secrets encrypts the TOTP secrets of users before they are stored
+	Secrets *helpers.SecretBox
+
+	// ActiveSessions maps the IDs of recently verified sessions to their user IDs,
+	// sparing the authentication middleware a database round trip on every request.
+	ActiveSessions *cache.Cache[string, string]
+
+	// Suggestions maps user IDs to the follow suggestions last worked out for them
+	Suggestions *cache.Cache[string, []models.Suggestion]
 }
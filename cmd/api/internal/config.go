@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Config holds configuration data passed via flags or dotenv
@@ -13,6 +16,43 @@ type Config struct {
 	Env       string
 	Port      int
 	JWTSecret string
+	AppURL    string
+
+	// JWT selects how tokens are signed.
+	// The RS256 and EdDSA keys are read from KeyDir, see helpers.KeySet for how to rotate them.
+	// HS256 tokens signed with JWTSecret up to LegacyAcceptUntil keep verifying under RS256 and EdDSA
+	// until they expire, and are rejected when LegacyAcceptUntil is not set.
+	JWT struct {
+		Algorithm         string
+		KeyDir            string
+		ActiveKeyID       string
+		LegacyAcceptUntil time.Time
+	}
+
+	// TOTPKey is the base64 encoded 32 byte key TOTP secrets are encrypted with
+	TOTPKey string
+
+	// RestrictUnverified limits users who have not verified their email address to read-only access
+	RestrictUnverified bool
+
+	// ExportDir is where personal data exports are kept until their download links expire
+	ExportDir string
+
+	// DeletionGracePeriod is how long deleted users have to change their minds by logging in before they are purged
+	DeletionGracePeriod time.Duration
+
+	// Password selects how passwords are hashed.
+	// Hashes made with another algorithm or other parameters are upgraded when their owner next logs in.
+	Password struct {
+		Algorithm  string
+		BcryptCost int
+
+		Argon2 struct {
+			Memory      int
+			Iterations  int
+			Parallelism int
+		}
+	}
 
 	Db struct {
 		DSN string
@@ -23,6 +63,19 @@ type Config struct {
 		APIKey    string
 		APISecret string
 	}
+
+	Mail struct {
+		Driver string
+		From   string
+		LogDir string
+
+		SMTP struct {
+			Host     string
+			Port     int
+			Username string
+			Password string
+		}
+	}
 }
 
 // Parse sets the fields of the Config to the data passed in via flags or dotenv
@@ -30,7 +83,25 @@ func (c *Config) Parse() {
 	// app details
 	flag.StringVar(&c.Env, "env", c.defaultEnv(), "Working Environment (development | staging | production)\nDotenv variable: ENV\n")
 	flag.IntVar(&c.Port, "port", c.defaultPort(), "API Server Port\nDotenv variable: PORT\n")
-	flag.StringVar(&c.JWTSecret, "jwt-secret", c.defaultJWTSecret(), "JWT Secret Key - Required\nDotenv variable: JWT_SECRET\n")
+	flag.StringVar(&c.JWTSecret, "jwt-secret", c.defaultJWTSecret(), "JWT Secret Key - Required by HS256 and jwt-legacy-accept-until\nDotenv variable: JWT_SECRET\n")
+	flag.StringVar(&c.TOTPKey, "totp-key", c.defaultTOTPKey(), "Base64 encoded 32 byte key TOTP secrets are encrypted with - Required\nDotenv variable: TOTP_KEY\n")
+	flag.StringVar(&c.AppURL, "app-url", c.defaultAppURL(), "Base URL used in links sent to users\nDotenv variable: APP_URL\n")
+	flag.BoolVar(&c.RestrictUnverified, "restrict-unverified", c.defaultRestrictUnverified(), "Give users with unverified email addresses read-only access\nDotenv variable: RESTRICT_UNVERIFIED\n")
+	flag.StringVar(&c.ExportDir, "export-dir", c.defaultExportDir(), "Directory personal data exports are written to\nDotenv variable: EXPORT_DIR\n")
+	flag.DurationVar(&c.DeletionGracePeriod, "deletion-grace-period", c.defaultDeletionGracePeriod(), "How long deleted users can log in to cancel the deletion before they are purged\nDotenv variable: DELETION_GRACE_PERIOD\n")
+
+	// jwt details
+	flag.StringVar(&c.JWT.Algorithm, "jwt-algorithm", c.defaultJWTAlgorithm(), "JWT Signing Algorithm (HS256 | RS256 | EdDSA)\nDotenv variable: JWT_ALGORITHM\n")
+	flag.StringVar(&c.JWT.KeyDir, "jwt-key-dir", c.defaultJWTKeyDir(), "Directory of PEM encoded <kid>.pem signing keys - Required by RS256 and EdDSA\nDotenv variable: JWT_KEY_DIR\n")
+	flag.StringVar(&c.JWT.ActiveKeyID, "jwt-active-key-id", c.defaultJWTActiveKeyID(), "ID of the key new tokens are signed with, the last private key by name is used when empty\nDotenv variable: JWT_ACTIVE_KEY_ID\n")
+	flag.TextVar(&c.JWT.LegacyAcceptUntil, "jwt-legacy-accept-until", c.defaultJWTLegacyAcceptUntil(), "RFC 3339 time HS256 signing stopped, HS256 tokens issued before it stay valid under RS256 and EdDSA until they expire\nDotenv variable: JWT_LEGACY_ACCEPT_UNTIL\n")
+
+	// password hashing details
+	flag.StringVar(&c.Password.Algorithm, "password-algorithm", c.defaultPasswordAlgorithm(), "Password Hashing Algorithm (bcrypt | argon2id)\nDotenv variable: PASSWORD_ALGORITHM\n")
+	flag.IntVar(&c.Password.BcryptCost, "bcrypt-cost", c.defaultBcryptCost(), "Bcrypt Cost\nDotenv variable: BCRYPT_COST\n")
+	flag.IntVar(&c.Password.Argon2.Memory, "argon2-memory", c.defaultArgon2Memory(), "Argon2id Memory in KiB\nDotenv variable: ARGON2_MEMORY\n")
+	flag.IntVar(&c.Password.Argon2.Iterations, "argon2-iterations", c.defaultArgon2Iterations(), "Argon2id Iterations\nDotenv variable: ARGON2_ITERATIONS\n")
+	flag.IntVar(&c.Password.Argon2.Parallelism, "argon2-parallelism", c.defaultArgon2Parallelism(), "Argon2id Parallelism\nDotenv variable: ARGON2_PARALLELISM\n")
 
 	// database details
 	flag.StringVar(&c.Db.DSN, "db-dsn", c.defaultDbDSN(), "Postgres Database DSN - Required\nDotenv variable: DB_DSN\n")
@@ -40,19 +111,84 @@ func (c *Config) Parse() {
 	flag.StringVar(&c.Cloudinary.APIKey, "cloudinary-api-key", c.defaultCloudinaryAPIKey(), "Cloudinary API Key\nDotenv variable: CLOUDINARY_API_KEY\n")
 	flag.StringVar(&c.Cloudinary.APISecret, "cloudinary-api-secret", c.defaultCloudinaryAPISecret(), "Cloudinary API Secret\nDotenv variable: CLOUDINARY_API_SECRET\n")
 
+	// mail details
+	flag.StringVar(&c.Mail.Driver, "mail-driver", c.defaultMailDriver(), "Mail Driver (smtp | log)\nDotenv variable: MAIL_DRIVER\n")
+	flag.StringVar(&c.Mail.From, "mail-from", c.defaultMailFrom(), "Sender Address of Outgoing Email\nDotenv variable: MAIL_FROM\n")
+	flag.StringVar(&c.Mail.LogDir, "mail-log-dir", c.defaultMailLogDir(), "Directory the log mail driver writes emails to, the log is used when empty\nDotenv variable: MAIL_LOG_DIR\n")
+	flag.StringVar(&c.Mail.SMTP.Host, "smtp-host", c.defaultSMTPHost(), "SMTP Server Host - Required by the smtp mail driver\nDotenv variable: SMTP_HOST\n")
+	flag.IntVar(&c.Mail.SMTP.Port, "smtp-port", c.defaultSMTPPort(), "SMTP Server Port\nDotenv variable: SMTP_PORT\n")
+	flag.StringVar(&c.Mail.SMTP.Username, "smtp-username", c.defaultSMTPUsername(), "SMTP Username\nDotenv variable: SMTP_USERNAME\n")
+	flag.StringVar(&c.Mail.SMTP.Password, "smtp-password", c.defaultSMTPPassword(), "SMTP Password\nDotenv variable: SMTP_PASSWORD\n")
+
 	flag.Parse()
 }
 
 // Validate ensures required flags or environment variables are set.
 func (c *Config) Validate() error {
-	if c.JWTSecret == "" {
-		return errors.New(validationMessage("jwt-secret", "JWT_SECRET"))
+	switch c.JWT.Algorithm {
+	case "HS256":
+		if c.JWTSecret == "" {
+			return errors.New(validationMessage("jwt-secret", "JWT_SECRET"))
+		}
+	case "RS256", "EdDSA":
+		if c.JWT.KeyDir == "" {
+			return errors.New(validationMessage("jwt-key-dir", "JWT_KEY_DIR"))
+		}
+		// a malformed cutover would silently reject every HS256 token still in use
+		if value, exists := os.LookupEnv("JWT_LEGACY_ACCEPT_UNTIL"); exists && c.JWT.LegacyAcceptUntil.IsZero() {
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return fmt.Errorf("the %q dotenv variable must be an RFC 3339 time", "JWT_LEGACY_ACCEPT_UNTIL")
+			}
+		}
+		if !c.JWT.LegacyAcceptUntil.IsZero() && c.JWTSecret == "" {
+			return errors.New(validationMessage("jwt-secret", "JWT_SECRET"))
+		}
+	default:
+		return fmt.Errorf("the %q flag or %q dotenv variable must be one of: HS256, RS256, EdDSA", "jwt-algorithm", "JWT_ALGORITHM")
+	}
+
+	if c.TOTPKey == "" {
+		return errors.New(validationMessage("totp-key", "TOTP_KEY"))
+	}
+
+	switch c.Password.Algorithm {
+	case "bcrypt":
+		if c.Password.BcryptCost < bcrypt.MinCost || c.Password.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("the %q flag or %q dotenv variable must be between %d and %d", "bcrypt-cost", "BCRYPT_COST", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case "argon2id":
+		if c.Password.Argon2.Memory < 1 || c.Password.Argon2.Iterations < 1 {
+			return fmt.Errorf("the %q and %q flags must be positive", "argon2-memory", "argon2-iterations")
+		}
+		if c.Password.Argon2.Parallelism < 1 || c.Password.Argon2.Parallelism > 255 {
+			return fmt.Errorf("the %q flag or %q dotenv variable must be between 1 and 255", "argon2-parallelism", "ARGON2_PARALLELISM")
+		}
+	default:
+		return fmt.Errorf("the %q flag or %q dotenv variable must be one of: bcrypt, argon2id", "password-algorithm", "PASSWORD_ALGORITHM")
+	}
+
+	if c.ExportDir == "" {
+		return errors.New(validationMessage("export-dir", "EXPORT_DIR"))
+	}
+
+	if c.DeletionGracePeriod < 0 {
+		return fmt.Errorf("the %q flag or %q dotenv variable must not be negative", "deletion-grace-period", "DELETION_GRACE_PERIOD")
 	}
 
 	if c.Db.DSN == "" {
 		return errors.New(validationMessage("db-dsn", "DB_DSN"))
 	}
 
+	switch c.Mail.Driver {
+	case "smtp":
+		if c.Mail.SMTP.Host == "" {
+			return errors.New(validationMessage("smtp-host", "SMTP_HOST"))
+		}
+	case "log":
+	default:
+		return fmt.Errorf("the %q flag or %q dotenv variable must be one of: smtp, log", "mail-driver", "MAIL_DRIVER")
+	}
+
 	return nil
 }
 
@@ -90,6 +226,111 @@ func (c *Config) defaultJWTSecret() string {
 	return defaultSecret
 }
 
+func (c *Config) defaultTOTPKey() string {
+	const defaultTOTPKey = ""
+
+	if value, exists := os.LookupEnv("TOTP_KEY"); exists {
+		return value
+	}
+	return defaultTOTPKey
+}
+
+func (c *Config) defaultJWTAlgorithm() string {
+	const defaultJWTAlgorithm = "HS256"
+
+	if value, exists := os.LookupEnv("JWT_ALGORITHM"); exists {
+		return value
+	}
+	return defaultJWTAlgorithm
+}
+
+func (c *Config) defaultJWTKeyDir() string {
+	const defaultJWTKeyDir = ""
+
+	if value, exists := os.LookupEnv("JWT_KEY_DIR"); exists {
+		return value
+	}
+	return defaultJWTKeyDir
+}
+
+func (c *Config) defaultJWTActiveKeyID() string {
+	const defaultJWTActiveKeyID = ""
+
+	if value, exists := os.LookupEnv("JWT_ACTIVE_KEY_ID"); exists {
+		return value
+	}
+	return defaultJWTActiveKeyID
+}
+
+func (c *Config) defaultJWTLegacyAcceptUntil() time.Time {
+	var defaultJWTLegacyAcceptUntil time.Time
+
+	if value, exists := os.LookupEnv("JWT_LEGACY_ACCEPT_UNTIL"); exists {
+		cutover, err := time.Parse(time.RFC3339, value)
+		if err == nil {
+			return cutover
+		}
+	}
+	return defaultJWTLegacyAcceptUntil
+}
+
+func (c *Config) defaultPasswordAlgorithm() string {
+	const defaultPasswordAlgorithm = "bcrypt"
+
+	if value, exists := os.LookupEnv("PASSWORD_ALGORITHM"); exists {
+		return value
+	}
+	return defaultPasswordAlgorithm
+}
+
+func (c *Config) defaultBcryptCost() int {
+	const defaultBcryptCost = 12
+
+	if value, exists := os.LookupEnv("BCRYPT_COST"); exists {
+		cost, err := strconv.Atoi(value)
+		if err == nil {
+			return cost
+		}
+	}
+	return defaultBcryptCost
+}
+
+func (c *Config) defaultArgon2Memory() int {
+	const defaultArgon2Memory = 64 * 1024
+
+	if value, exists := os.LookupEnv("ARGON2_MEMORY"); exists {
+		memory, err := strconv.Atoi(value)
+		if err == nil {
+			return memory
+		}
+	}
+	return defaultArgon2Memory
+}
+
+func (c *Config) defaultArgon2Iterations() int {
+	const defaultArgon2Iterations = 3
+
+	if value, exists := os.LookupEnv("ARGON2_ITERATIONS"); exists {
+		iterations, err := strconv.Atoi(value)
+		if err == nil {
+			return iterations
+		}
+	}
+	return defaultArgon2Iterations
+}
+
+func (c *Config) defaultArgon2Parallelism() int {
+	const defaultArgon2Parallelism = 2
+
+	if value, exists := os.LookupEnv("ARGON2_PARALLELISM"); exists {
+		parallelism, err := strconv.Atoi(value)
+		if err == nil {
+			return parallelism
+		}
+	}
+	return defaultArgon2Parallelism
+}
+
 func (c *Config) defaultDbDSN() string {
 	const defaultDSN = ""
 
@@ -125,3 +366,111 @@ func (c *Config) defaultCloudinaryAPISecret() string {
 	}
 	return defaultCloudinaryAPISecret
 }
+
+func (c *Config) defaultAppURL() string {
+	const defaultAppURL = "http://localhost:5000"
+
+	if value, exists := os.LookupEnv("APP_URL"); exists {
+		return value
+	}
+	return defaultAppURL
+}
+
+func (c *Config) defaultRestrictUnverified() bool {
+	const defaultRestrictUnverified = false
+
+	if value, exists := os.LookupEnv("RESTRICT_UNVERIFIED"); exists {
+		restrict, err := strconv.ParseBool(value)
+		if err == nil {
+			return restrict
+		}
+	}
+	return defaultRestrictUnverified
+}
+
+func (c *Config) defaultMailDriver() string {
+	const defaultMailDriver = "log"
+
+	if value, exists := os.LookupEnv("MAIL_DRIVER"); exists {
+		return value
+	}
+	return defaultMailDriver
+}
+
+func (c *Config) defaultMailFrom() string {
+	const defaultMailFrom = "MeMo <no-reply@localhost>"
+
+	if value, exists := os.LookupEnv("MAIL_FROM"); exists {
+		return value
+	}
+	return defaultMailFrom
+}
+
+func (c *Config) defaultMailLogDir() string {
+	const defaultMailLogDir = ""
+
+	if value, exists := os.LookupEnv("MAIL_LOG_DIR"); exists {
+		return value
+	}
+	return defaultMailLogDir
+}
+
+func (c *Config) defaultSMTPHost() string {
+	const defaultSMTPHost = ""
+
+	if value, exists := os.LookupEnv("SMTP_HOST"); exists {
+		return value
+	}
+	return defaultSMTPHost
+}
+
+func (c *Config) defaultSMTPPort() int {
+	const defaultSMTPPort = 587
+
+	if value, exists := os.LookupEnv("SMTP_PORT"); exists {
+		port, err := strconv.Atoi(value)
+		if err == nil {
+			return port
+		}
+	}
+	return defaultSMTPPort
+}
+
+func (c *Config) defaultSMTPUsername() string {
+	const defaultSMTPUsername = ""
+
+	if value, exists := os.LookupEnv("SMTP_USERNAME"); exists {
+		return value
+	}
+	return defaultSMTPUsername
+}
+
+func (c *Config) defaultSMTPPassword() string {
+	const defaultSMTPPassword = ""
+
+	if value, exists := os.LookupEnv("SMTP_PASSWORD"); exists {
+		return value
+	}
+	return defaultSMTPPassword
+}
+
+func (c *Config) defaultDeletionGracePeriod() time.Duration {
+	const defaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+	if value, exists := os.LookupEnv("DELETION_GRACE_PERIOD"); exists {
+		gracePeriod, err := time.ParseDuration(value)
+		if err == nil {
+			return gracePeriod
+		}
+	}
+	return defaultDeletionGracePeriod
+}
+
+func (c *Config) defaultExportDir() string {
+	const defaultExportDir = "exports"
+
+	if value, exists := os.LookupEnv("EXPORT_DIR"); exists {
+		return value
+	}
+	return defaultExportDir
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -12,7 +13,7 @@ import (
 	"github.com/akinolaemmanuel49/memo-api/domain/repository"
 )
 
-// Authentication validates the provided access token and authenticates users.
+// Authentication validates the provided access token or personal access token and authenticates users.
 func Authentication(app internal.Application) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctx.Header("Vary", "Authorization")
@@ -33,16 +34,20 @@ func Authentication(app internal.Application) gin.HandlerFunc {
 			return
 		}
 
-		// validate access token
-		claims, err := helpers.ValidateToken(app.Config.JWTSecret, headerParts[1])
-		if err != nil {
-			ctx.Header("WWW-Authenticate", "Bearer")
-			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, err)
+		// personal access tokens carry scopes, access tokens are bound to a session
+		var userID string
+		var ok bool
+		if helpers.IsPersonalAccessToken(headerParts[1]) {
+			userID, ok = authenticatePersonalAccessToken(app, ctx, headerParts[1])
+		} else {
+			userID, ok = authenticateAccessToken(app, ctx, headerParts[1])
+		}
+		if !ok {
 			return
 		}
 
 		// retrieve associated user
-		user, err := app.Repositories.Users.GetById(claims.Subject)
+		user, err := app.Repositories.Users.GetById(userID)
 		if err != nil {
 			switch {
 			case errors.Is(err, repository.ErrRecordNotFound):
@@ -58,8 +63,87 @@ func Authentication(app internal.Application) gin.HandlerFunc {
 			return
 		}
 
+		if user.Suspended {
+			helpers.HandleErrorResponse(ctx, http.StatusForbidden, errors.New("account has been suspended"))
+			return
+		}
+
 		// set user in context for further use
 		helpers.ContextSetUser(ctx, user)
 		ctx.Next()
 	}
 }
+
+// authenticateAccessToken validates a JWT access token and the session it belongs to,
+// saving the session ID in the request context. An error response is written when false is returned.
+func authenticateAccessToken(app internal.Application, ctx *gin.Context, signedToken string) (string, bool) {
+	claims, err := helpers.ValidateToken(app.Keys, signedToken, helpers.TokenTypeAccess)
+	if err != nil {
+		ctx.Header("WWW-Authenticate", "Bearer")
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, err)
+		return "", false
+	}
+
+	// access tokens are always bound to a session
+	if claims.SessionID == "" {
+		ctx.Header("WWW-Authenticate", "Bearer")
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid or expired token"))
+		return "", false
+	}
+
+	// reject tokens whose session has been revoked, recently verified sessions are served from memory
+	if userID, ok := app.ActiveSessions.Get(claims.SessionID); !ok || userID != claims.Subject {
+		if err := app.Repositories.Sessions.Touch(claims.SessionID, claims.Subject); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrRecordNotFound):
+				ctx.Header("WWW-Authenticate", "Bearer")
+				helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("session has been revoked"))
+
+			default:
+				helpers.HandleInternalServerError(ctx, err)
+			}
+
+			return "", false
+		}
+		app.ActiveSessions.Set(claims.SessionID, claims.Subject)
+	}
+
+	helpers.ContextSetSessionID(ctx, claims.SessionID)
+	return claims.Subject, true
+}
+
+// authenticatePersonalAccessToken looks up a personal access token by its hash, saving its scopes in the
+// request context. An error response is written when false is returned.
+func authenticatePersonalAccessToken(app internal.Application, ctx *gin.Context, token string) (string, bool) {
+	accessToken, err := app.Repositories.Tokens.GetPersonalAccessTokenByHash(helpers.HashToken(token))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.Header("WWW-Authenticate", "Bearer")
+			helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid or expired token"))
+
+		default:
+			helpers.HandleInternalServerError(ctx, err)
+		}
+
+		return "", false
+	}
+
+	now := time.Now()
+	if accessToken.Revoked || (accessToken.ExpiresAt.Valid && now.After(accessToken.ExpiresAt.Time)) {
+		ctx.Header("WWW-Authenticate", "Bearer")
+		helpers.HandleErrorResponse(ctx, http.StatusUnauthorized, errors.New("invalid or expired token"))
+		return "", false
+	}
+
+	// recording every use would cost a write per request, a coarser last used time is enough
+	if !accessToken.LastUsedAt.Valid || now.Sub(accessToken.LastUsedAt.Time) > helpers.SessionCacheDuration {
+		if err := app.Repositories.Tokens.TouchPersonalAccessToken(accessToken.ID); err != nil {
+			helpers.HandleInternalServerError(ctx, err)
+			return "", false
+		}
+	}
+
+	helpers.ContextSetScopes(ctx, accessToken.Scopes)
+	return accessToken.UserID, true
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+)
+
+// LoadRoles loads the roles of the user in context along with the permissions they grant, for RequirePermission.
+// Only routes checking permissions use it, sparing every other request the lookup.
+func LoadRoles(app internal.Application) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := helpers.ContextGetUser(ctx)
+
+		roles, err := app.Repositories.Roles.GetByUserID(user.ID)
+		if err != nil {
+			helpers.HandleInternalServerError(ctx, err)
+			return
+		}
+		for _, role := range roles {
+			user.Roles = append(user.Roles, role.Name)
+			for _, permission := range role.Permissions {
+				if !slices.Contains(user.Permissions, permission) {
+					user.Permissions = append(user.Permissions, permission)
+				}
+			}
+		}
+
+		helpers.ContextSetUser(ctx, user)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+)
+
+// RequirePermission only lets users in context through if one of their roles grants the permission.
+// The roles of the user must have been loaded by LoadRoles.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if user := helpers.ContextGetUser(ctx); !slices.Contains(user.Permissions, permission) {
+			helpers.HandleErrorResponse(ctx, http.StatusForbidden, errors.New("permission denied"))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+)
+
+// RequireScope limits requests authenticated with a personal access token to those its scopes cover.
+// Safe methods need readScope and all other methods need writeScope.
+// Requests authenticated with an access token are not restricted.
+func RequireScope(readScope string, writeScope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scopes, ok := helpers.ContextGetScopes(ctx)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		scope := writeScope
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			scope = readScope
+		}
+
+		if !slices.Contains(scopes, scope) {
+			helpers.HandleErrorResponse(ctx, http.StatusForbidden, fmt.Errorf("token is missing the %q scope", scope))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// RequireSession rejects requests authenticated with a personal access token,
+// keeping account management such as creating more tokens to interactive logins.
+func RequireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := helpers.ContextGetScopes(ctx); ok {
+			helpers.HandleErrorResponse(ctx, http.StatusForbidden, errors.New("personal access tokens cannot be used here"))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+)
+
+// newScopeTestRouter returns a router that authenticates every request with a personal access token holding
+// scopes, or with an access token when scopes is nil, before running middleware.
+func newScopeTestRouter(scopes []string, middleware gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		if scopes != nil {
+			helpers.ContextSetScopes(ctx, scopes)
+		}
+		ctx.Next()
+	}, middleware)
+	router.Any("/", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		method string
+		want   int
+	}{
+		{"access token reading", nil, http.MethodGet, http.StatusOK},
+		{"access token writing", nil, http.MethodPost, http.StatusOK},
+		{"read scope with GET", []string{helpers.ScopeMemoRead}, http.MethodGet, http.StatusOK},
+		{"read scope with HEAD", []string{helpers.ScopeMemoRead}, http.MethodHead, http.StatusOK},
+		{"read scope with OPTIONS", []string{helpers.ScopeMemoRead}, http.MethodOptions, http.StatusOK},
+		{"read scope with POST", []string{helpers.ScopeMemoRead}, http.MethodPost, http.StatusForbidden},
+		{"read scope with PUT", []string{helpers.ScopeMemoRead}, http.MethodPut, http.StatusForbidden},
+		{"read scope with PATCH", []string{helpers.ScopeMemoRead}, http.MethodPatch, http.StatusForbidden},
+		{"read scope with DELETE", []string{helpers.ScopeMemoRead}, http.MethodDelete, http.StatusForbidden},
+		{"write scope with GET", []string{helpers.ScopeMemoWrite}, http.MethodGet, http.StatusForbidden},
+		{"write scope with POST", []string{helpers.ScopeMemoWrite}, http.MethodPost, http.StatusOK},
+		{"write scope with DELETE", []string{helpers.ScopeMemoWrite}, http.MethodDelete, http.StatusOK},
+		{"both scopes with GET", []string{helpers.ScopeMemoRead, helpers.ScopeMemoWrite}, http.MethodGet, http.StatusOK},
+		{"both scopes with POST", []string{helpers.ScopeMemoRead, helpers.ScopeMemoWrite}, http.MethodPost, http.StatusOK},
+		{"other resource scopes with GET", []string{helpers.ScopeSocialRead, helpers.ScopeProfileRead}, http.MethodGet, http.StatusForbidden},
+		{"other resource scopes with POST", []string{helpers.ScopeSocialWrite, helpers.ScopeProfileWrite}, http.MethodPost, http.StatusForbidden},
+		{"no scopes with GET", []string{}, http.MethodGet, http.StatusForbidden},
+		{"no scopes with POST", []string{}, http.MethodPost, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newScopeTestRouter(tt.scopes, RequireScope(helpers.ScopeMemoRead, helpers.ScopeMemoWrite))
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tt.method, "/", nil))
+			if recorder.Code != tt.want {
+				t.Errorf("%s with scopes %v: got status %d, want %d", tt.method, tt.scopes, recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   int
+	}{
+		{"access token", nil, http.StatusOK},
+		{"personal access token", helpers.Scopes, http.StatusForbidden},
+		{"personal access token without scopes", []string{}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newScopeTestRouter(tt.scopes, RequireSession())
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+			if recorder.Code != tt.want {
+				t.Errorf("got status %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
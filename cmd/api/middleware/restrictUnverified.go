@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+)
+
+// RestrictUnverified gives users in context who have not verified their email address read-only access.
+// It does nothing unless Config.RestrictUnverified is set.
+func RestrictUnverified(app internal.Application) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !app.Config.RestrictUnverified {
+			ctx.Next()
+			return
+		}
+
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+
+		if user := helpers.ContextGetUser(ctx); !user.IsActivated {
+			helpers.HandleErrorResponse(ctx, http.StatusForbidden, errors.New("email address has not been verified"))
+			return
+		}
+		ctx.Next()
+	}
+}
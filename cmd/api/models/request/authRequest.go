@@ -0,0 +1,34 @@
+package request
+
+import (
+	"errors"
+
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+// Login holds the credentials sent to the token endpoint.
+// Identifier is a username or an email address. Email is still accepted from older clients.
+type Login struct {
+	Identifier *string `json:"identifier" validate:"omitempty,min=1,max=255"`
+	Email      *string `json:"email" validate:"omitempty,email"`
+	Password   *string `json:"password" validate:"required"`
+}
+
+// LoginIdentifier returns the username or email address the login is for.
+func (l Login) LoginIdentifier() string {
+	if l.Identifier != nil {
+		return *l.Identifier
+	}
+	return helpers.SafeDereference(l.Email)
+}
+
+// ValidateRequired verifies that an identifier and a password are provided.
+func (l Login) ValidateRequired() error {
+	if l.Identifier == nil && l.Email == nil {
+		return errors.New("identifier is required")
+	}
+	if l.Password == nil {
+		return errors.New("password is required")
+	}
+	return nil
+}
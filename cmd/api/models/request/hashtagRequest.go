@@ -0,0 +1,11 @@
+package request
+
+import "github.com/akinolaemmanuel49/memo-api/internal/helpers"
+
+// Hashtag is a hashtag given in a path or query parameter, with or without its leading #.
+type Hashtag string
+
+// Name returns the normalized hashtag, or an empty string if it is not a valid hashtag.
+func (h Hashtag) Name() string {
+	return helpers.NormalizeHashtag(string(h))
+}
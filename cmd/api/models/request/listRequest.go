@@ -0,0 +1,59 @@
+package request
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type List struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+	Private     *bool   `json:"private" validate:"omitempty"`
+}
+
+const (
+	ListFieldName = iota
+)
+
+// ToModel converts the request to a new list, private unless stated otherwise.
+func (l List) ToModel() models.List {
+	list := models.List{
+		Name:        strings.TrimSpace(helpers.SafeDereference(l.Name)),
+		Description: strings.TrimSpace(helpers.SafeDereference(l.Description)),
+		Private:     true,
+	}
+	if l.Private != nil {
+		list.Private = *l.Private
+	}
+	return list
+}
+
+// ApplyTo returns a copy of list with the fields provided in the request changed.
+func (l List) ApplyTo(list models.List) models.List {
+	if l.Name != nil {
+		list.Name = strings.TrimSpace(*l.Name)
+	}
+	if l.Description != nil {
+		list.Description = strings.TrimSpace(*l.Description)
+	}
+	if l.Private != nil {
+		list.Private = *l.Private
+	}
+	return list
+}
+
+// ValidateRequired verifies that the required fields for the request are provided.
+func (l List) ValidateRequired(required ...int) error {
+	for _, field := range required {
+		switch field {
+		case ListFieldName:
+			if l.Name == nil {
+				return errors.New("name is required")
+			}
+		}
+	}
+	return nil
+}
@@ -8,7 +8,9 @@ import (
 )
 
 type TextMemo struct {
-	Content *string `json:"content" validate:"omitempty"`
+	Content    *string `json:"content" validate:"omitempty"`
+	Audience   *string `json:"audience" validate:"omitempty,oneof=everyone close_friends"`
+	Visibility *string `json:"visibility" validate:"omitempty,oneof=public followers unlisted private"`
 }
 
 const (
@@ -17,7 +19,9 @@ const (
 
 func (tm TextMemo) ToModel() models.Memo {
 	return models.Memo{
-		Content: helpers.SafeDereference(tm.Content),
+		Content:    helpers.SafeDereference(tm.Content),
+		Audience:   helpers.SafeDereference(tm.Audience),
+		Visibility: helpers.SafeDereference(tm.Visibility),
 	}
 }
 
@@ -116,3 +120,29 @@ func (vm VideoMemo) ValidateRequired(required ...int) error {
 	}
 	return nil
 }
+
+type MemoVisibility struct {
+	Visibility *string `json:"visibility" validate:"required,oneof=public followers unlisted private"`
+}
+
+type EditMemo struct {
+	Content    *string `json:"content" validate:"omitempty"`
+	Caption    *string `json:"caption" validate:"omitempty"`
+	Transcript *string `json:"transcript" validate:"omitempty"`
+	Version    *int    `json:"version" validate:"required,min=0"`
+}
+
+// ApplyTo returns a copy of memo with the fields provided in the request changed.
+func (em EditMemo) ApplyTo(memo models.Memo) models.Memo {
+	if em.Content != nil {
+		memo.Content = *em.Content
+	}
+	if em.Caption != nil {
+		memo.Caption = *em.Caption
+	}
+	if em.Transcript != nil {
+		memo.Transcript = *em.Transcript
+	}
+	memo.Version = *em.Version
+	return memo
+}
@@ -0,0 +1,25 @@
+package request
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type MutedWord struct {
+	Word      *string    `json:"word" validate:"required,min=1,max=100"`
+	ExpiresAt *time.Time `json:"expiresAt" validate:"omitempty"`
+}
+
+func (mw MutedWord) ToModel() models.MutedWord {
+	mutedWord := models.MutedWord{
+		Word: strings.TrimSpace(helpers.SafeDereference(mw.Word)),
+	}
+	if mw.ExpiresAt != nil {
+		mutedWord.ExpiresAt = sql.NullTime{Time: mw.ExpiresAt.UTC(), Valid: true}
+	}
+	return mutedWord
+}
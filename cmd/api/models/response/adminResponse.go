@@ -0,0 +1,104 @@
+package response
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+// AdminUser describes a user to administrators, including their moderation state and roles.
+type AdminUser struct {
+	ID            string    `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	FirstName     string    `json:"firstName"`
+	LastName      string    `json:"lastName"`
+	EmailVerified bool      `json:"emailVerified"`
+	Suspended     bool      `json:"suspended"`
+	Deleted       bool      `json:"deleted"`
+	Roles         []string  `json:"roles"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func AdminUserResponseFromModel(user models.User) AdminUser {
+	roles := user.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	return AdminUser{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		EmailVerified: user.IsActivated,
+		Suspended:     user.Suspended,
+		Deleted:       user.Deleted,
+		Roles:         roles,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+func MultipleAdminUserResponseFromModel(users []models.User) []AdminUser {
+	var userResponses []AdminUser
+	for _, user := range users {
+		userResponse := AdminUserResponseFromModel(user)
+		userResponses = append(userResponses, userResponse)
+	}
+	return userResponses
+}
+
+// AdminMemo describes a memo to administrators, including its moderation state.
+type AdminMemo struct {
+	Memo
+	Suspended bool `json:"suspended"`
+}
+
+func AdminMemoResponseFromModel(memo models.Memo) AdminMemo {
+	return AdminMemo{
+		Memo:      MemoResponseFromModel(memo),
+		Suspended: memo.Suspended,
+	}
+}
+
+func MultipleAdminMemoResponseFromModel(memos []models.Memo) []AdminMemo {
+	var memoResponses []AdminMemo
+	for _, memo := range memos {
+		memoResponse := AdminMemoResponseFromModel(memo)
+		memoResponses = append(memoResponses, memoResponse)
+	}
+	return memoResponses
+}
+
+type Lockout struct {
+	ID          string    `json:"id"`
+	Scope       string    `json:"scope"`
+	Subject     string    `json:"subject"`
+	IPAddress   string    `json:"ipAddress"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"lockedUntil"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func LockoutResponseFromModel(lockout models.Lockout) Lockout {
+	return Lockout{
+		ID:          lockout.ID,
+		Scope:       lockout.Scope,
+		Subject:     lockout.Subject,
+		IPAddress:   lockout.IPAddress,
+		Failures:    lockout.Failures,
+		LockedUntil: lockout.LockedUntil,
+		CreatedAt:   lockout.CreatedAt,
+	}
+}
+
+func MultipleLockoutResponseFromModel(lockouts []models.Lockout) []Lockout {
+	var lockoutResponses []Lockout
+	for _, lockout := range lockouts {
+		lockoutResponse := LockoutResponseFromModel(lockout)
+		lockoutResponses = append(lockoutResponses, lockoutResponse)
+	}
+	return lockoutResponses
+}
@@ -1,6 +1,26 @@
 package response
 
+import "github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+
 type AuthResponse struct {
 	Tokens `json:",omitempty"`
 	User   `json:"profile,omitempty"`
 }
+
+// TwoFactorChallenge is returned instead of tokens when a user with two-factor authentication enabled logs in.
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	ChallengeToken    string `json:"challengeToken"`
+	ExpiresAt         int64  `json:"expiresAt"`
+}
+
+// TwoFactorEnrollment carries the secret a user adds to their authenticator app.
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthURI"`
+}
+
+// JWKS is a JSON Web Key Set holding the public token verification keys.
+type JWKS struct {
+	Keys []helpers.JWK `json:"keys"`
+}
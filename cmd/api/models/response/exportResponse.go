@@ -0,0 +1,73 @@
+package response
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+// DataExport describes a personal data export.
+// DownloadURL is only set in the response to starting the export, it cannot be retrieved again.
+type DataExport struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	DownloadURL string     `json:"downloadURL,omitempty"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+func DataExportResponseFromModel(export models.DataExport) DataExport {
+	exportResponse := DataExport{
+		ID:        export.ID,
+		Status:    export.Status,
+		ExpiresAt: export.ExpiresAt,
+		CreatedAt: export.CreatedAt,
+	}
+	if export.CompletedAt.Valid {
+		exportResponse.CompletedAt = &export.CompletedAt.Time
+	}
+	return exportResponse
+}
+
+// ExportLike is a like as written to a data export.
+type ExportLike struct {
+	MemoID    string    `json:"memoID"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func MultipleExportLikeFromModel(likes []models.Like) []ExportLike {
+	exportLikes := make([]ExportLike, 0, len(likes))
+	for _, like := range likes {
+		exportLikes = append(exportLikes, ExportLike{MemoID: like.MemoID, CreatedAt: like.CreatedAt})
+	}
+	return exportLikes
+}
+
+// ExportShare is a share as written to a data export.
+type ExportShare struct {
+	MemoID    string    `json:"memoID"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func MultipleExportShareFromModel(shares []models.Share) []ExportShare {
+	exportShares := make([]ExportShare, 0, len(shares))
+	for _, share := range shares {
+		exportShares = append(exportShares, ExportShare{MemoID: share.MemoID, CreatedAt: share.CreatedAt})
+	}
+	return exportShares
+}
+
+// ExportFollow is a follower or followed user as written to a data export, leaving out their personal details.
+type ExportFollow struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+func MultipleExportFollowFromModel(users []models.User) []ExportFollow {
+	exportFollows := make([]ExportFollow, 0, len(users))
+	for _, user := range users {
+		exportFollows = append(exportFollows, ExportFollow{ID: user.ID, Username: user.Username})
+	}
+	return exportFollows
+}
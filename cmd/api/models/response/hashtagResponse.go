@@ -0,0 +1,23 @@
+package response
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+type Hashtag struct {
+	Name      string `json:"name"`
+	MemoCount int64  `json:"memoCount"`
+}
+
+func HashtagResponseFromModel(hashtag models.Hashtag) Hashtag {
+	return Hashtag{
+		Name:      hashtag.Name,
+		MemoCount: hashtag.MemoCount,
+	}
+}
+
+func MultipleHashtagResponseFromModel(hashtags []models.Hashtag) []Hashtag {
+	hashtagResponses := make([]Hashtag, 0, len(hashtags))
+	for _, hashtag := range hashtags {
+		hashtagResponses = append(hashtagResponses, HashtagResponseFromModel(hashtag))
+	}
+	return hashtagResponses
+}
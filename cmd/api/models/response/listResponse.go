@@ -0,0 +1,41 @@
+package response
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+type List struct {
+	ID            string    `json:"id"`
+	OwnerID       string    `json:"ownerID"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	Private       bool      `json:"private"`
+	MemberCount   int64     `json:"memberCount"`
+	FollowerCount int64     `json:"followerCount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func ListResponseFromModel(list models.List) List {
+	return List{
+		ID:            list.ID,
+		OwnerID:       list.OwnerID,
+		Name:          list.Name,
+		Description:   list.Description,
+		Private:       list.Private,
+		MemberCount:   list.MemberCount,
+		FollowerCount: list.FollowerCount,
+		CreatedAt:     list.CreatedAt,
+		UpdatedAt:     list.UpdatedAt,
+	}
+}
+
+func MultipleListResponseFromModel(lists []models.List) []List {
+	listResponses := make([]List, 0, len(lists))
+	for _, list := range lists {
+		listResponses = append(listResponses, ListResponseFromModel(list))
+	}
+	return listResponses
+}
@@ -7,21 +7,26 @@ import (
 )
 
 type Memo struct {
-	ID         string    `json:"id,omitempty"`
-	MemoType   string    `json:"memo_type"`
-	Content    string    `json:"content"`
-	Likes      int64     `json:"likes,omitempty"`
-	Shares     int64     `json:"shares,omitempty"`
-	Caption    string    `json:"caption,omitempty"`
-	Transcript string    `json:"transcript,omitempty"`
-	Deleted    bool      `json:"deleted,omitempty"`
-	CreatedAt  time.Time `json:"created_at,omitempty"`
-	UpdatedAt  time.Time `json:"updated_at,omitempty"`
-	OwnerID    string    `json:"owner_id,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	MemoType   string     `json:"memo_type"`
+	Content    string     `json:"content"`
+	Likes      int64      `json:"likes,omitempty"`
+	Shares     int64      `json:"shares,omitempty"`
+	Caption    string     `json:"caption,omitempty"`
+	Transcript string     `json:"transcript,omitempty"`
+	Audience   string     `json:"audience"`
+	Visibility string     `json:"visibility"`
+	Deleted    bool       `json:"deleted,omitempty"`
+	Edited     bool       `json:"edited"`
+	EditedAt   *time.Time `json:"edited_at,omitempty"`
+	Version    int        `json:"version"`
+	CreatedAt  time.Time  `json:"created_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at,omitempty"`
+	OwnerID    string     `json:"owner_id,omitempty"`
 }
 
 func MemoResponseFromModel(memo models.Memo) Memo {
-	return Memo{
+	memoResponse := Memo{
 		ID:         memo.ID,
 		MemoType:   memo.MemoType,
 		Content:    memo.Content,
@@ -29,11 +34,19 @@ func MemoResponseFromModel(memo models.Memo) Memo {
 		Shares:     memo.Shares,
 		Caption:    memo.Caption,
 		Transcript: memo.Transcript,
+		Audience:   memo.Audience,
+		Visibility: memo.Visibility,
 		Deleted:    memo.Deleted,
+		Edited:     memo.EditedAt.Valid,
+		Version:    memo.Version,
 		CreatedAt:  memo.CreatedAt,
 		UpdatedAt:  memo.UpdatedAt,
 		OwnerID:    memo.OwnerID,
 	}
+	if memo.EditedAt.Valid {
+		memoResponse.EditedAt = &memo.EditedAt.Time
+	}
+	return memoResponse
 }
 
 func MultipleMemoResponseFromModel(memos []models.Memo) []Memo {
@@ -44,3 +57,30 @@ func MultipleMemoResponseFromModel(memos []models.Memo) []Memo {
 	}
 	return memoResponses
 }
+
+// MemoRevision is a memo as it was before one of its edits.
+type MemoRevision struct {
+	Version    int       `json:"version"`
+	Content    string    `json:"content"`
+	Caption    string    `json:"caption,omitempty"`
+	Transcript string    `json:"transcript,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func MemoRevisionResponseFromModel(revision models.MemoRevision) MemoRevision {
+	return MemoRevision{
+		Version:    revision.Version,
+		Content:    revision.Content,
+		Caption:    revision.Caption,
+		Transcript: revision.Transcript,
+		CreatedAt:  revision.CreatedAt,
+	}
+}
+
+func MultipleMemoRevisionResponseFromModel(revisions []models.MemoRevision) []MemoRevision {
+	revisionResponses := make([]MemoRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		revisionResponses = append(revisionResponses, MemoRevisionResponseFromModel(revision))
+	}
+	return revisionResponses
+}
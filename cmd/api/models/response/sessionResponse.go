@@ -0,0 +1,38 @@
+package response
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+type Session struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IPAddress  string    `json:"ipAddress"`
+	UserAgent  string    `json:"userAgent"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+func SessionResponseFromModel(session models.Session, currentSessionID string) Session {
+	return Session{
+		ID:         session.ID,
+		Device:     session.Device,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		Current:    session.ID == currentSessionID,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+	}
+}
+
+func MultipleSessionResponseFromModel(sessions []models.Session, currentSessionID string) []Session {
+	var sessionResponses []Session
+	for _, session := range sessions {
+		sessionResponse := SessionResponseFromModel(session, currentSessionID)
+		sessionResponses = append(sessionResponses, sessionResponse)
+	}
+	return sessionResponses
+}
@@ -46,3 +46,31 @@ func MultipleCommentResponseFromModel(comments []models.Comment) []Comment {
 	}
 	return commentResponses
 }
+
+type MutedWord struct {
+	ID        string     `json:"id"`
+	Word      string     `json:"word"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func MutedWordResponseFromModel(mutedWord models.MutedWord) MutedWord {
+	mutedWordResponse := MutedWord{
+		ID:        mutedWord.ID,
+		Word:      mutedWord.Word,
+		CreatedAt: mutedWord.CreatedAt,
+	}
+	if mutedWord.ExpiresAt.Valid {
+		mutedWordResponse.ExpiresAt = &mutedWord.ExpiresAt.Time
+	}
+	return mutedWordResponse
+}
+
+func MultipleMutedWordResponseFromModel(mutedWords []models.MutedWord) []MutedWord {
+	var mutedWordResponses []MutedWord
+	for _, mutedWord := range mutedWords {
+		mutedWordResponse := MutedWordResponseFromModel(mutedWord)
+		mutedWordResponses = append(mutedWordResponses, mutedWordResponse)
+	}
+	return mutedWordResponses
+}
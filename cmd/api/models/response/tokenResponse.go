@@ -1,7 +1,51 @@
 package response
 
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
 type Tokens struct {
-	AccessToken  string `json:"accessToken,omitempty"`
-	RefreshToken string `json:"refreshToken,omitempty"`
-	ExpiresAt    int64  `json:"expiresAt,omitempty"`
+	AccessToken      string `json:"accessToken,omitempty"`
+	RefreshToken     string `json:"refreshToken,omitempty"`
+	ExpiresAt        int64  `json:"expiresAt,omitempty"`
+	RefreshExpiresAt int64  `json:"refreshExpiresAt,omitempty"`
+}
+
+// PersonalAccessToken describes a personal access token.
+// Token is only set in the response to creating the token, it cannot be retrieved again.
+type PersonalAccessToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func PersonalAccessTokenResponseFromModel(accessToken models.PersonalAccessToken) PersonalAccessToken {
+	tokenResponse := PersonalAccessToken{
+		ID:        accessToken.ID,
+		Name:      accessToken.Name,
+		Scopes:    accessToken.Scopes,
+		CreatedAt: accessToken.CreatedAt,
+	}
+	if accessToken.ExpiresAt.Valid {
+		tokenResponse.ExpiresAt = &accessToken.ExpiresAt.Time
+	}
+	if accessToken.LastUsedAt.Valid {
+		tokenResponse.LastUsedAt = &accessToken.LastUsedAt.Time
+	}
+	return tokenResponse
+}
+
+func MultiplePersonalAccessTokenResponseFromModel(accessTokens []models.PersonalAccessToken) []PersonalAccessToken {
+	var tokenResponses []PersonalAccessToken
+	for _, accessToken := range accessTokens {
+		tokenResponse := PersonalAccessTokenResponseFromModel(accessToken)
+		tokenResponses = append(tokenResponses, tokenResponse)
+	}
+	return tokenResponses
 }
@@ -15,7 +15,9 @@ type User struct {
 	AvatarURL      string    `json:"avatarURL,omitempty"`
 	Status         string    `json:"status"`
 	About          string    `json:"about"`
+	EmailVerified  bool      `json:"emailVerified"`
 	Deleted        bool      `json:"deleted"`
+	Private        bool      `json:"private"`
 	FollowerCount  int64     `json:"followerCount"`
 	FollowingCount int64     `json:"followingCount"`
 	CreatedAt      time.Time `json:"createdAt,omitempty"`
@@ -32,7 +34,9 @@ func UserResponseFromModel(user models.User) User {
 		AvatarURL:      user.AvatarURL,
 		Status:         user.Status,
 		About:          user.About,
+		EmailVerified:  user.IsActivated,
 		Deleted:        user.Deleted,
+		Private:        user.Private,
 		FollowerCount:  user.FollowerCount,
 		FollowingCount: user.FollowingCount,
 		CreatedAt:      user.CreatedAt,
@@ -48,3 +52,78 @@ func MultipleUserResponseFromModel(users []models.User) []User {
 	}
 	return userResponses
 }
+
+// Profile is the public profile of a user as seen by another user, leaving out the email address.
+type Profile struct {
+	User
+	Relationship Relationship `json:"relationship"`
+}
+
+// Relationship describes how the viewing user and the user whose profile is shown are connected.
+type Relationship struct {
+	Following   bool `json:"following"`
+	FollowedBy  bool `json:"followedBy"`
+	Blocking    bool `json:"blocking"`
+	BlockedBy   bool `json:"blockedBy"`
+	Muting      bool `json:"muting"`
+	Requested   bool `json:"requested"`
+	RequestedBy bool `json:"requestedBy"`
+}
+
+func ProfileResponseFromModel(user models.User, relationship models.Relationship) Profile {
+	return Profile{
+		User:         PublicUserResponseFromModel(user),
+		Relationship: RelationshipResponseFromModel(relationship),
+	}
+}
+
+func RelationshipResponseFromModel(relationship models.Relationship) Relationship {
+	return Relationship{
+		Following:   relationship.Following,
+		FollowedBy:  relationship.FollowedBy,
+		Blocking:    relationship.Blocking,
+		BlockedBy:   relationship.BlockedBy,
+		Muting:      relationship.Muting,
+		Requested:   relationship.Requested,
+		RequestedBy: relationship.RequestedBy,
+	}
+}
+
+// Suggestion is a user suggested to follow, along with why they were suggested.
+type Suggestion struct {
+	User
+	MutualCount int64 `json:"mutualCount"`
+	FollowsYou  bool  `json:"followsYou"`
+}
+
+func SuggestionResponseFromModel(suggestion models.Suggestion) Suggestion {
+	return Suggestion{
+		User:        PublicUserResponseFromModel(suggestion.User),
+		MutualCount: suggestion.MutualCount,
+		FollowsYou:  suggestion.FollowsViewer,
+	}
+}
+
+func MultipleSuggestionResponseFromModel(suggestions []models.Suggestion) []Suggestion {
+	var suggestionResponses []Suggestion
+	for _, suggestion := range suggestions {
+		suggestionResponse := SuggestionResponseFromModel(suggestion)
+		suggestionResponses = append(suggestionResponses, suggestionResponse)
+	}
+	return suggestionResponses
+}
+
+// PublicUserResponseFromModel describes a user to other users, leaving out the email address.
+func PublicUserResponseFromModel(user models.User) User {
+	userResponse := UserResponseFromModel(user)
+	userResponse.Email = ""
+	return userResponse
+}
+
+func MultiplePublicUserResponseFromModel(users []models.User) []User {
+	userResponses := make([]User, 0, len(users))
+	for _, user := range users {
+		userResponses = append(userResponses, PublicUserResponseFromModel(user))
+	}
+	return userResponses
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/handlers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/middleware"
+)
+
+func adminRoutes(app internal.Application, routes *gin.Engine) {
+	adminHandler := handlers.NewAdminHandler(app)
+	admin := routes.Group("/admin")
+	admin.Use(middleware.Authentication(app), middleware.ContextUserSoftDelete(), middleware.RequireSession(), middleware.LoadRoles(app))
+
+	users := admin.Group("/users")
+	{
+		users.GET("", middleware.RequirePermission(helpers.PermissionUsersRead), adminHandler.GetUsers)
+		users.POST("/:id/suspend", middleware.RequirePermission(helpers.PermissionUsersSuspend), adminHandler.SuspendUser)
+		users.POST("/:id/restore", middleware.RequirePermission(helpers.PermissionUsersSuspend), adminHandler.RestoreUser)
+		users.POST("/:id/roles", middleware.RequirePermission(helpers.PermissionRolesManage), adminHandler.AssignRole)
+		users.DELETE("/:id/roles/:role", middleware.RequirePermission(helpers.PermissionRolesManage), adminHandler.RemoveRole)
+	}
+
+	memos := admin.Group("/memos")
+	{
+		memos.GET("", middleware.RequirePermission(helpers.PermissionMemosRead), adminHandler.GetMemos)
+		memos.POST("/:id/suspend", middleware.RequirePermission(helpers.PermissionMemosSuspend), adminHandler.SuspendMemo)
+		memos.POST("/:id/restore", middleware.RequirePermission(helpers.PermissionMemosSuspend), adminHandler.RestoreMemo)
+	}
+
+	lockouts := admin.Group("/lockouts")
+	lockouts.Use(middleware.RequirePermission(helpers.PermissionLockoutsRead))
+	{
+		lockouts.GET("", adminHandler.GetLockouts)
+	}
+}
@@ -5,14 +5,35 @@ import (
 
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/handlers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/middleware"
 )
 
 func authRoutes(app internal.Application, routes *gin.Engine) {
 	authHandler := handlers.NewAuthHandler(app)
+	routes.GET("/.well-known/jwks.json", authHandler.JWKS)
+
 	auth := routes.Group("/auth")
 	{
 		auth.POST("/signup", authHandler.SignUp)
 		auth.POST("/token", authHandler.Token)
 		auth.POST("/refresh-token", authHandler.RefreshToken)
+		auth.POST("/verify-email", authHandler.VerifyEmail)
+		auth.POST("/password/forgot", authHandler.ForgotPassword)
+		auth.POST("/password/reset", authHandler.ResetPassword)
+		auth.POST("/2fa/verify", authHandler.VerifyTwoFactor)
+	}
+
+	authenticated := auth.Group("")
+	authenticated.Use(middleware.Authentication(app), middleware.ContextUserSoftDelete(), middleware.RequireSession())
+	{
+		authenticated.POST("/verify-email/resend", authHandler.ResendVerificationEmail)
+		authenticated.POST("/2fa/enroll", authHandler.EnrollTwoFactor)
+		authenticated.POST("/2fa/confirm", authHandler.ConfirmTwoFactor)
+		authenticated.POST("/2fa/disable", authHandler.DisableTwoFactor)
+		authenticated.POST("/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+		authenticated.POST("/logout", authHandler.Logout)
+		authenticated.GET("/sessions", authHandler.GetSessions)
+		authenticated.DELETE("/sessions", authHandler.RevokeAllSessions)
+		authenticated.DELETE("/sessions/:id", authHandler.RevokeSession)
 	}
 }
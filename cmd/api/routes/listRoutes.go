@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/handlers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/middleware"
+)
+
+func listRoutes(app internal.Application, routes *gin.Engine) {
+	listHandler := handlers.NewListHandler(app)
+	list := routes.Group("/lists")
+	list.Use(
+		middleware.Authentication(app),
+		middleware.ContextUserSoftDelete(),
+		middleware.RestrictUnverified(app),
+		middleware.RequireScope(helpers.ScopeSocialRead, helpers.ScopeSocialWrite),
+	)
+	{
+		list.GET("", listHandler.GetOwnLists)
+		list.POST("", listHandler.Create)
+		list.GET("/followed", listHandler.GetFollowedLists)
+		list.GET("/user/:userID", listHandler.GetUserLists)
+		list.GET("/:id", listHandler.Get)
+		list.PUT("/:id", listHandler.Update)
+		list.DELETE("/:id", listHandler.Delete)
+		list.GET("/:id/members", listHandler.GetMembers)
+		list.POST("/:id/members/:userID", listHandler.AddMember)
+		list.DELETE("/:id/members/:userID", listHandler.RemoveMember)
+		list.POST("/:id/follow", listHandler.Follow)
+		list.DELETE("/:id/follow", listHandler.Unfollow)
+		list.GET("/:id/feed", listHandler.GetFeed)
+	}
+}
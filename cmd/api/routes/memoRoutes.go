@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/handlers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/middleware"
 )
@@ -11,7 +12,12 @@ import (
 func memoRoutes(app internal.Application, routes *gin.Engine) {
 	memoHandler := handlers.NewMemoHandler(app)
 	memo := routes.Group("/memo")
-	memo.Use(middleware.Authentication(app), middleware.ContextUserSoftDelete())
+	memo.Use(
+		middleware.Authentication(app),
+		middleware.ContextUserSoftDelete(),
+		middleware.RestrictUnverified(app),
+		middleware.RequireScope(helpers.ScopeMemoRead, helpers.ScopeMemoWrite),
+	)
 	{
 		memo.POST("/text", memoHandler.CreateTextMemo)
 		memo.POST("/image", memoHandler.CreateImageMemo)
@@ -19,6 +25,9 @@ func memoRoutes(app internal.Application, routes *gin.Engine) {
 		memo.POST("/audio", memoHandler.CreateAudioMemo)
 		memo.GET("/:memoID", memoHandler.GetMemo)
 		memo.DELETE("/:memoID", memoHandler.DeleteMemo)
+		memo.PATCH("/:memoID", memoHandler.EditMemo)
+		memo.GET("/:memoID/revisions", memoHandler.GetMemoRevisions)
+		memo.PATCH("/:memoID/visibility", memoHandler.UpdateVisibility)
 		memo.POST("/like/:memoID", memoHandler.LikeMemo)
 		memo.POST("/unlike/:memoID", memoHandler.UnlikeMemo)
 		memo.POST("/share/:memoID", memoHandler.ShareMemo)
@@ -15,7 +15,7 @@ func Router(app internal.Application) *gin.Engine {
 	router.Use(cors.New(cors.Config{
 		AllowAllOrigins:  true,
 		AllowMethods:     []string{"PUT", "PATCH", "GET", "POST", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "X-Device-Name"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           helpers.MaxAge,
@@ -24,7 +24,12 @@ func Router(app internal.Application) *gin.Engine {
 	// set routes
 	authRoutes(app, router)
 	userRoutes(app, router)
+	tokenRoutes(app, router)
+	exportRoutes(app, router)
 	socialRoutes(app, router)
+	listRoutes(app, router)
+	tagRoutes(app, router)
 	memoRoutes(app, router)
+	adminRoutes(app, router)
 	return router
 }
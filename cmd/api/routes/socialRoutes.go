@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/handlers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/middleware"
 )
@@ -11,13 +12,36 @@ import (
 func socialRoutes(app internal.Application, routes *gin.Engine) {
 	socialHandler := handlers.NewSocialHandler(app)
 	social := routes.Group("/social")
-	social.Use(middleware.Authentication(app), middleware.ContextUserSoftDelete())
+	social.Use(
+		middleware.Authentication(app),
+		middleware.ContextUserSoftDelete(),
+		middleware.RestrictUnverified(app),
+		middleware.RequireScope(helpers.ScopeSocialRead, helpers.ScopeSocialWrite),
+	)
 	{
 		social.POST("/follow", socialHandler.Follow)
 		social.POST("/unfollow", socialHandler.Unfollow)
+		social.GET("/follow-requests", socialHandler.GetFollowRequests)
+		social.GET("/suggestions", socialHandler.GetSuggestions)
+		social.GET("/relationship/:userID", socialHandler.GetRelationship)
+		social.GET("/mutuals/:userID", socialHandler.GetMutualFollowers)
+		social.POST("/follow-requests/approve", socialHandler.ApproveFollowRequest)
+		social.POST("/follow-requests/reject", socialHandler.RejectFollowRequest)
 		social.POST("/comment/:memoID", socialHandler.CreateTextComment)
 		social.POST("/comment/reply/:memoID/:parentID", socialHandler.CreateTextReply)
 		social.GET("/reply/:commentID/replies", socialHandler.GetReplies)
 		social.GET("/comment/:memoID", socialHandler.GetComments)
+		social.POST("/block/:userID", socialHandler.Block)
+		social.DELETE("/block/:userID", socialHandler.Unblock)
+		social.GET("/blocked", socialHandler.GetBlockedUsers)
+		social.POST("/mute/:userID", socialHandler.Mute)
+		social.DELETE("/mute/:userID", socialHandler.Unmute)
+		social.GET("/muted", socialHandler.GetMutedUsers)
+		social.POST("/muted-words", socialHandler.MuteWord)
+		social.GET("/muted-words", socialHandler.GetMutedWords)
+		social.DELETE("/muted-words/:id", socialHandler.UnmuteWord)
+		social.GET("/close-friends", socialHandler.GetCloseFriends)
+		social.POST("/close-friends/:userID", socialHandler.AddCloseFriend)
+		social.DELETE("/close-friends/:userID", socialHandler.RemoveCloseFriend)
 	}
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/handlers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/middleware"
+)
+
+func tagRoutes(app internal.Application, routes *gin.Engine) {
+	tagHandler := handlers.NewTagHandler(app)
+	tag := routes.Group("/tags")
+	tag.Use(
+		middleware.Authentication(app),
+		middleware.ContextUserSoftDelete(),
+		middleware.RestrictUnverified(app),
+		middleware.RequireScope(helpers.ScopeMemoRead, helpers.ScopeMemoWrite),
+	)
+	{
+		tag.GET("/search", tagHandler.Search)
+		tag.GET("/:tag/memos", tagHandler.GetMemos)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/handlers"
+	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/middleware"
 )
@@ -11,14 +12,48 @@ import (
 func userRoutes(app internal.Application, routes *gin.Engine) {
 	userHandler := handlers.NewUserHandler(app)
 	user := routes.Group("/users")
-	user.Use(middleware.Authentication(app), middleware.ContextUserSoftDelete())
+	user.Use(
+		middleware.Authentication(app),
+		middleware.ContextUserSoftDelete(),
+		middleware.RestrictUnverified(app),
+		middleware.RequireScope(helpers.ScopeProfileRead, helpers.ScopeProfileWrite),
+	)
 	{
 		user.GET("", userHandler.Get)
 		user.PUT("", userHandler.Update)
 		user.DELETE("", userHandler.Delete)
 		user.GET("/all", userHandler.GetAll)
+		user.GET("/search", userHandler.Search)
 		user.GET("/followers", userHandler.GetFollowers)
 		user.GET("/following", userHandler.GetFollowing)
 		user.DELETE("/avatar", userHandler.DeleteAvatar)
+		user.GET("/by-username/:username", userHandler.GetByUsername)
+		user.GET("/:id", userHandler.GetByID)
+		user.GET("/:id/followers", userHandler.GetFollowersByID)
+		user.GET("/:id/following", userHandler.GetFollowingByID)
 	}
 }
+
+func tokenRoutes(app internal.Application, routes *gin.Engine) {
+	userHandler := handlers.NewUserHandler(app)
+	tokens := routes.Group("/users/tokens")
+	tokens.Use(middleware.Authentication(app), middleware.ContextUserSoftDelete(), middleware.RequireSession())
+	{
+		tokens.GET("", userHandler.GetTokens)
+		tokens.POST("", userHandler.CreateToken)
+		tokens.DELETE("/:id", userHandler.RevokeToken)
+	}
+}
+
+func exportRoutes(app internal.Application, routes *gin.Engine) {
+	userHandler := handlers.NewUserHandler(app)
+	exports := routes.Group("/users/export")
+	exports.Use(middleware.Authentication(app), middleware.ContextUserSoftDelete(), middleware.RequireSession())
+	{
+		exports.POST("", userHandler.Export)
+		exports.GET("/:id", userHandler.GetExport)
+	}
+
+	// download links are opened outside the app, so the token in the link stands in for authentication
+	routes.GET("/users/export/:id/download/:token", userHandler.DownloadExport)
+}
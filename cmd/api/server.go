@@ -2,6 +2,7 @@ package main
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,25 +10,66 @@ import (
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/helpers"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/internal"
 	"github.com/akinolaemmanuel49/memo-api/cmd/api/routes"
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
 	"github.com/akinolaemmanuel49/memo-api/domain/repository"
 	"github.com/akinolaemmanuel49/memo-api/infrastructure/database/postgres"
+	"github.com/akinolaemmanuel49/memo-api/infrastructure/mail"
 	"github.com/akinolaemmanuel49/memo-api/infrastructure/storage"
+	"github.com/akinolaemmanuel49/memo-api/internal/cache"
 )
 
 // serveApp starts the server and handles its shutdown
 func serveApp(config internal.Config, db *sql.DB) error {
+	keys, err := newKeySet(config)
+	if err != nil {
+		return err
+	}
+
+	passwords, err := helpers.NewPasswordHasher(
+		config.Password.Algorithm,
+		config.Password.BcryptCost,
+		helpers.Argon2Params{
+			Memory:      uint32(config.Password.Argon2.Memory),
+			Iterations:  uint32(config.Password.Argon2.Iterations),
+			Parallelism: uint8(config.Password.Argon2.Parallelism),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := helpers.NewSecretBox(config.TOTPKey)
+	if err != nil {
+		return err
+	}
+
 	app := internal.Application{
-		Config: config,
+		Config:    config,
+		Keys:      keys,
+		Passwords: passwords,
+		Secrets:   secrets,
 		Repositories: repository.Repositories{
-			Users:  postgres.NewUserInfrastructure(db),
-			Social: postgres.NewSocialInfrastructure(db),
-			Memo:   postgres.NewMemoInfrastructure(db),
+			Users:         postgres.NewUserInfrastructure(db),
+			Social:        postgres.NewSocialInfrastructure(db),
+			Memo:          postgres.NewMemoInfrastructure(db),
+			Tokens:        postgres.NewTokenInfrastructure(db),
+			Sessions:      postgres.NewSessionInfrastructure(db),
+			TwoFactor:     postgres.NewTwoFactorInfrastructure(db),
+			LoginThrottle: postgres.NewLoginThrottleInfrastructure(db),
+			Roles:         postgres.NewRoleInfrastructure(db),
+			Moderation:    postgres.NewModerationInfrastructure(db),
+			DataExports:   postgres.NewDataExportInfrastructure(db),
+			Lists:         postgres.NewListInfrastructure(db),
+			Hashtags:      postgres.NewHashtagInfrastructure(db),
 			File: storage.NewFileInfrastructure(
 				config.Cloudinary.CloudName,
 				config.Cloudinary.APIKey,
 				config.Cloudinary.APISecret,
 			),
+			Mailer: newMailer(config),
 		},
+		ActiveSessions: cache.New[string, string](helpers.SessionCacheDuration),
+		Suggestions:    cache.New[string, []models.Suggestion](helpers.SuggestionCacheDuration),
 	}
 
 	srv := http.Server{
@@ -38,6 +80,14 @@ func serveApp(config internal.Config, db *sql.DB) error {
 		WriteTimeout: helpers.WriteTimeout,
 	}
 
+	// encrypt the TOTP secrets stored before secrets were encrypted
+	if err := sealTOTPSecrets(app); err != nil {
+		return err
+	}
+
+	// purge deleted users and expired data exports in the background
+	go runCleanup(app)
+
 	// start server
 	log.Printf("starting %s server on %s\n", app.Config.Env, srv.Addr)
 	if err := srv.ListenAndServe(); err != nil {
@@ -47,3 +97,55 @@ func serveApp(config internal.Config, db *sql.DB) error {
 	log.Println("server stopped")
 	return nil
 }
+
+// sealTOTPSecrets encrypts the TOTP secrets that were stored in plaintext, in batches of helpers.TOTPSealBatchSize.
+func sealTOTPSecrets(app internal.Application) error {
+	for {
+		enrollments, err := app.Repositories.TwoFactor.GetUnsealedSecrets(helpers.SealedSecretPrefix, helpers.TOTPSealBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, enrollment := range enrollments {
+			sealed, err := app.Secrets.Seal(enrollment.TOTPSecret)
+			if err != nil {
+				return err
+			}
+			// a new enrollment in the meantime stored a sealed secret already
+			err = app.Repositories.TwoFactor.ReplaceSecret(enrollment.UserID, enrollment.TOTPSecret, sealed)
+			if err != nil && !errors.Is(err, repository.ErrConcurrentUpdate) {
+				return err
+			}
+		}
+
+		if len(enrollments) < helpers.TOTPSealBatchSize {
+			return nil
+		}
+	}
+}
+
+// newMailer returns the mailer selected by the mail driver configuration.
+func newMailer(config internal.Config) repository.Mailer {
+	switch config.Mail.Driver {
+	case "smtp":
+		return mail.NewSMTPMailer(
+			config.Mail.SMTP.Host,
+			config.Mail.SMTP.Port,
+			config.Mail.SMTP.Username,
+			config.Mail.SMTP.Password,
+			config.Mail.From,
+		)
+	default:
+		return mail.NewLogMailer(config.Mail.From, config.Mail.LogDir)
+	}
+}
+
+// newKeySet returns the token signing keys selected by the JWT configuration.
+func newKeySet(config internal.Config) (*helpers.KeySet, error) {
+	switch config.JWT.Algorithm {
+	case helpers.AlgorithmRS256, helpers.AlgorithmEdDSA:
+		return helpers.LoadKeySet(config.JWT.Algorithm, config.JWT.KeyDir, config.JWT.ActiveKeyID, config.JWTSecret, config.JWT.LegacyAcceptUntil)
+	default:
+		return helpers.NewHMACKeySet(config.JWTSecret), nil
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	DataExportStatusPending   = "pending"
+	DataExportStatusCompleted = "completed"
+	DataExportStatusFailed    = "failed"
+)
+
+// DataExport is an archive of everything a user owns, built in the background.
+// Pending exports expire if they are not completed in time, completed exports can be downloaded once until ExpiresAt.
+type DataExport struct {
+	ID           string
+	UserID       string
+	Status       string
+	TokenHash    string
+	FilePath     string
+	Failure      string
+	ExpiresAt    time.Time
+	CompletedAt  sql.NullTime
+	DownloadedAt sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Version      int
+}
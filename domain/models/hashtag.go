@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Hashtag is a normalized hashtag found in the text of text memos or the captions of media memos.
+type Hashtag struct {
+	ID        string
+	Name      string
+	MemoCount int64
+	CreatedAt time.Time
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// List is a curated set of users whose memos make up a timeline of its own, apart from the users its owner follows.
+// Private lists are only seen by their owner, public lists can be seen and followed by anyone.
+type List struct {
+	ID            string
+	OwnerID       string
+	Name          string
+	Description   string
+	Private       bool
+	MemberCount   int64
+	FollowerCount int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Version       int
+}
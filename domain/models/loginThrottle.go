@@ -0,0 +1,31 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LoginFailure counts the recent failed logins of an account or IP address.
+type LoginFailure struct {
+	Scope        string
+	Subject      string
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Version      int
+}
+
+// Lockout records an account or IP address being locked out after too many failed logins.
+type Lockout struct {
+	ID          string
+	Scope       string
+	Subject     string
+	IPAddress   string
+	Failures    int
+	LockedUntil time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     int
+}
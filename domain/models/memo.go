@@ -1,6 +1,24 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
+
+// memo audiences: memos for close friends are only shown to the close friends of their owner
+const (
+	AudienceEveryone     = "everyone"
+	AudienceCloseFriends = "close_friends"
+)
+
+// memo visibilities: public memos are shown to everyone, followers-only memos to the followers of their owner,
+// unlisted memos to everyone but left out of the global feed, and private memos to their owner alone
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityUnlisted  = "unlisted"
+	VisibilityPrivate   = "private"
+)
 
 type Memo struct {
 	ID         string
@@ -10,13 +28,28 @@ type Memo struct {
 	Shares     int64
 	Caption    string
 	Transcript string
+	Audience   string
+	Visibility string
 	Deleted    bool
+	Suspended  bool
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+	EditedAt   sql.NullTime
 	OwnerID    string
 	Version    int
 }
 
+// MemoRevision is a memo as it was before one of its edits, Version being the version of the memo it replaced.
+type MemoRevision struct {
+	ID         string
+	MemoID     string
+	Version    int
+	Content    string
+	Caption    string
+	Transcript string
+	CreatedAt  time.Time
+}
+
 type Like struct {
 	ID        string
 	MemoID    string
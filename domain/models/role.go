@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Role is a named set of permissions granted to users.
+type Role struct {
+	ID          string
+	Name        string
+	Permissions []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     int
+}
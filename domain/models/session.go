@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID         string
+	UserID     string
+	Device     string
+	IPAddress  string
+	UserAgent  string
+	Revoked    bool
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Version    int
+}
@@ -14,6 +14,84 @@ type Follow struct {
 	Version    int
 }
 
+// FollowRequest is a follow of a private user awaiting their approval.
+type FollowRequest struct {
+	ID         string
+	FollowerID string
+	SubjectID  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Version    int
+}
+
+// Block stops two users from seeing or interacting with each other, whichever of them is the blocker.
+type Block struct {
+	ID        string
+	BlockerID string
+	BlockedID string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+// Mute hides the memos and comments of the muted user from the muter, without the muted user knowing.
+type Mute struct {
+	ID        string
+	MuterID   string
+	MutedID   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+// CloseFriend makes a user part of the close friends of another user, who can share memos with them alone.
+type CloseFriend struct {
+	ID        string
+	UserID    string
+	FriendID  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+// MutedWord hides memos and comments containing a word, phrase or hashtag from the user who muted it,
+// until ExpiresAt if it is set.
+type MutedWord struct {
+	ID        string
+	UserID    string
+	Word      string
+	ExpiresAt sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+// Suggestion is a user suggested to follow, along with why they were suggested.
+type Suggestion struct {
+	User User
+
+	// MutualCount is how many of the users followed by the viewer follow the suggested user
+	MutualCount int64
+	// FollowsViewer is set when the suggested user follows the viewer
+	FollowsViewer bool
+	// ActiveFollowerCount is how many recently active users follow the suggested user
+	ActiveFollowerCount int64
+}
+
+// Relationship describes how a viewing user and another user are connected.
+type Relationship struct {
+	Following  bool
+	FollowedBy bool
+	Blocking   bool
+	BlockedBy  bool
+	// Muting is set when the viewer has muted the subject. Whether the subject has muted the viewer is never revealed.
+	Muting bool
+
+	// Requested and RequestedBy are set while a follow request between the users awaits approval
+	Requested   bool
+	RequestedBy bool
+}
+
 type Comment struct {
 	ID          string
 	OwnerID     string
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RefreshToken is a stored refresh token.
+// Tokens rotated from the same login share a FamilyID, which is the ID of their Session.
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	Revoked   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+type PasswordResetToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+// PersonalAccessToken is a long-lived token a user creates for scripts and bots.
+// Requests made with it may only reach routes covered by its Scopes.
+type PersonalAccessToken struct {
+	ID         string
+	UserID     string
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	Revoked    bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Version    int
+}
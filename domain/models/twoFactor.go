@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// TwoFactor is the TOTP enrollment of a user.
+// It is pending until the user proves their authenticator works, at which point Enabled is set.
+type TwoFactor struct {
+	UserID       string
+	TOTPSecret   string
+	Enabled      bool
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Version      int
+}
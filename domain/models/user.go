@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type User struct {
 	ID             string
@@ -14,9 +17,22 @@ type User struct {
 	Status         string
 	IsActivated    bool
 	Deleted        bool
+	Suspended      bool
+	Private        bool
+	PurgeAfter     sql.NullTime
 	FollowerCount  int64
 	FollowingCount int64
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Version        int
+
+	// Roles and Permissions are loaded separately from the rest of the user
+	Roles       []string
+	Permissions []string
+}
+
+// UserMedia identifies the uploaded files of a user by the memo and comment IDs they were uploaded under.
+type UserMedia struct {
+	MemoIDs    []string
+	CommentIDs []string
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+type DataExportRepository interface {
+	Create(export *models.DataExport) (models.DataExport, error)
+	Get(id string, userID string) (models.DataExport, error)
+	GetByTokenHash(tokenHash string) (models.DataExport, error)
+	Complete(id string, filePath string, expiresAt time.Time) error
+	Fail(id string, failure string) error
+	MarkDownloaded(id string) error
+	GetExpired(before time.Time, limit int) ([]models.DataExport, error)
+	Delete(id string) error
+}
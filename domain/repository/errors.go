@@ -3,12 +3,28 @@ package repository
 import "errors"
 
 var (
-	ErrDuplicateDetails   = errors.New("username or email already exists")
-	ErrRecordNotFound     = errors.New("no matching record found")
-	ErrRecordDeleted      = errors.New("record has been deleted")
-	ErrUnapprovedFileType = errors.New("provided file type is not allowed")
-	ErrDuplicateFollow    = errors.New("identical follow instance already exists")
-	ErrCheckFollow        = errors.New("followerID and subjectID must not be the same")
-	ErrMemoIDQueryMissing = errors.New("memoID is missing in the URL query parameter")
-	ErrConcurrentUpdate   = errors.New("concurrent update detected")
+	ErrDuplicateDetails       = errors.New("username or email already exists")
+	ErrRecordNotFound         = errors.New("no matching record found")
+	ErrRecordDeleted          = errors.New("record has been deleted")
+	ErrUnapprovedFileType     = errors.New("provided file type is not allowed")
+	ErrDuplicateFollow        = errors.New("identical follow instance already exists")
+	ErrCheckFollow            = errors.New("followerID and subjectID must not be the same")
+	ErrDuplicateFollowRequest = errors.New("follow request has already been sent")
+	ErrPrivateAccount         = errors.New("this account is private")
+	ErrDuplicateBlock         = errors.New("user is already blocked")
+	ErrCheckBlock             = errors.New("users cannot block themselves")
+	ErrBlocked                = errors.New("this action is not allowed between users who have blocked one another")
+	ErrDuplicateMute          = errors.New("user is already muted")
+	ErrCheckMute              = errors.New("users cannot mute themselves")
+	ErrDuplicateMutedWord     = errors.New("word is already muted")
+	ErrDuplicateCloseFriend   = errors.New("user is already a close friend")
+	ErrCheckCloseFriend       = errors.New("users cannot add themselves to their close friends")
+	ErrDuplicateList          = errors.New("a list with this name already exists")
+	ErrDuplicateListMember    = errors.New("user is already a member of this list")
+	ErrDuplicateListFollow    = errors.New("list is already followed")
+	ErrMemoIDQueryMissing     = errors.New("memoID is missing in the URL query parameter")
+	ErrConcurrentUpdate       = errors.New("concurrent update detected")
+	ErrTokenReused            = errors.New("token has already been used")
+	ErrTwoFactorEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrExportInProgress       = errors.New("an export is already in progress")
 )
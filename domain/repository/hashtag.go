@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+type HashtagRepository interface {
+	Search(prefix string, viewerID string, limit int) ([]models.Hashtag, error)
+	GetMemos(name string, viewerID string, page, pageSize int) ([]models.Memo, error)
+}
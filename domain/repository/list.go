@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+type ListRepository interface {
+	Create(list *models.List) (models.List, error)
+	Get(id string) (models.List, error)
+	GetByOwnerID(ownerID string, includePrivate bool, page, pageSize int) ([]models.List, error)
+	GetFollowedByUser(userID string, page, pageSize int) ([]models.List, error)
+	Update(id string, updatedList models.List) (models.List, error)
+	Delete(id string) error
+	AddMember(listID, userID string) error
+	RemoveMember(listID, userID string) error
+	GetMembers(listID string, page, pageSize int) ([]models.User, error)
+	Follow(listID, userID string) error
+	Unfollow(listID, userID string) error
+	GetFeed(listID, viewerID string, page, pageSize int) ([]models.Memo, error)
+}
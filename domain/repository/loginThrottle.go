@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+type LoginThrottleRepository interface {
+	GetFailure(scope, subject string) (models.LoginFailure, error)
+	RecordFailure(scope, subject string, window time.Duration) (models.LoginFailure, error)
+	ResetFailures(scope, subject string) error
+	Lock(lockout *models.Lockout) (models.Lockout, error)
+	GetLockouts(page, pageSize int) ([]models.Lockout, error)
+}
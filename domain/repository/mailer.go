@@ -0,0 +1,7 @@
+package repository
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+type Mailer interface {
+	Send(email models.Email) error
+}
@@ -7,12 +7,16 @@ type MemoRepository interface {
 	GetMemo(id string) (models.Memo, error)
 	LikeMemo(likerID string, memoID string) (models.Like, error)
 	UnlikeMemo(likerID string, memoID string) error
-	GetAllMemos(page, pageSize int) ([]models.Memo, error)
+	GetAllMemos(viewerID string, page, pageSize int) ([]models.Memo, error)
 	GetMemosByFollowing(ownerID string, page, pageSize int) ([]models.Memo, error)
 	Update(id string, updatedMemo models.Memo) (models.Memo, error)
+	Edit(id string, editedMemo models.Memo) (models.Memo, error)
+	GetRevisions(memoID string, page, pageSize int) ([]models.MemoRevision, error)
 	Delete(id string, deletedMemo models.Memo) (models.Memo, error)
 	ShareMemo(sharerID string, memoID string) (models.Share, error)
 	UnshareMemo(sharerID string, memoID string) error
-	GetMemosByOwnerID(ownerID string, page, pageSize int) ([]models.Memo, error)
+	GetMemosByOwnerID(ownerID string, viewerID string, page, pageSize int) ([]models.Memo, error)
+	GetLikesByUserID(userID string, page, pageSize int) ([]models.Like, error)
+	GetSharesByUserID(userID string, page, pageSize int) ([]models.Share, error)
 	//ReportMemo(id string) error
 }
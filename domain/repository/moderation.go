@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+// ModerationRepository gives administrators access to users and memos regardless of whether they are suspended.
+type ModerationRepository interface {
+	GetUsers(suspendedOnly bool, page, pageSize int) ([]models.User, error)
+	SetUserSuspended(id string, suspended bool) (models.User, error)
+	GetMemos(suspendedOnly bool, page, pageSize int) ([]models.Memo, error)
+	SetMemoSuspended(id string, suspended bool) (models.Memo, error)
+}
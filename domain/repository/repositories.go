@@ -2,8 +2,18 @@ package repository
 
 // Repositories encapsulates all available repositories for easy reuse.
 type Repositories struct {
-	Social SocialRepository
-	Users  UserRepository
-	File   FileRepository
-	Memo   MemoRepository
+	Social        SocialRepository
+	Users         UserRepository
+	File          FileRepository
+	Memo          MemoRepository
+	Tokens        TokenRepository
+	Sessions      SessionRepository
+	TwoFactor     TwoFactorRepository
+	LoginThrottle LoginThrottleRepository
+	Roles         RoleRepository
+	Moderation    ModerationRepository
+	DataExports   DataExportRepository
+	Lists         ListRepository
+	Hashtags      HashtagRepository
+	Mailer        Mailer
 }
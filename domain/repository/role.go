@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+type RoleRepository interface {
+	GetByUserID(userID string) ([]models.Role, error)
+	Assign(userID string, roleName string) error
+	Remove(userID string, roleName string) error
+}
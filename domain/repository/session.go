@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+type SessionRepository interface {
+	Create(session *models.Session) (models.Session, error)
+	GetActiveByUserID(userID string) ([]models.Session, error)
+	Touch(id string, userID string) error
+	RotateRefreshToken(usedTokenID string, refreshToken *models.RefreshToken) (models.RefreshToken, error)
+	Revoke(id string, userID string) error
+	RevokeAll(userID string) ([]string, error)
+}
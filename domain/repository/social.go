@@ -1,15 +1,42 @@
 package repository
 
-import "github.com/akinolaemmanuel49/memo-api/domain/models"
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
 
 type SocialRepository interface {
 	Follow(followerID, subjectID string) (models.Follow, error)
 	Unfollow(followerID, subjectID string) (models.Follow, error)
+	GetRelationship(viewerID, subjectID string) (models.Relationship, error)
+	GetMutualFollowers(viewerID, subjectID string, page, pageSize int) ([]models.User, error)
+	GetSuggestions(userID string, activeSince time.Time, limit int) ([]models.Suggestion, error)
+	RequestFollow(followerID, subjectID string) (models.FollowRequest, error)
+	GetFollowRequests(subjectID string, page, pageSize int) ([]models.User, error)
+	ApproveFollowRequest(subjectID, followerID string) (models.Follow, error)
+	ApproveAllFollowRequests(subjectID string) error
+	DeleteFollowRequest(followerID, subjectID string) error
+	Block(blockerID, blockedID string) (models.Block, error)
+	Unblock(blockerID, blockedID string) error
+	GetBlockedUsers(blockerID string, page, pageSize int) ([]models.User, error)
+	IsBlocked(userID, otherUserID string) (bool, error)
+	Mute(muterID, mutedID string) (models.Mute, error)
+	Unmute(muterID, mutedID string) error
+	GetMutedUsers(muterID string, page, pageSize int) ([]models.User, error)
+	MuteWord(mutedWord *models.MutedWord) (models.MutedWord, error)
+	UnmuteWord(userID, mutedWordID string) error
+	GetMutedWords(userID string) ([]models.MutedWord, error)
+	AddCloseFriend(userID, friendID string) (models.CloseFriend, error)
+	RemoveCloseFriend(userID, friendID string) error
+	GetCloseFriends(userID string, page, pageSize int) ([]models.User, error)
+	IsCloseFriend(userID, friendID string) (bool, error)
 	CreateComment(comment *models.Comment) (models.Comment, error)
 	GetComment(commentID string) (models.Comment, error)
 	UpdateComment(commentID string, updatedComment models.Comment) (models.Comment, error)
-	GetCommentsByMemoID(memoID string, page, pageSize int) ([]models.Comment, error)
-	GetRepliesByParentID(parentID string, page, pageSize int) ([]models.Comment, error)
+	GetCommentsByMemoID(memoID string, viewerID string, page, pageSize int) ([]models.Comment, error)
+	GetRepliesByParentID(parentID string, viewerID string, page, pageSize int) ([]models.Comment, error)
+	GetCommentsByOwnerID(ownerID string, page, pageSize int) ([]models.Comment, error)
 	//GetRepliesByParentID(parentID string, page, pageSize int) ([]models.Comment, error)
 	//GetReplies(ID string, page, pageSize int) ([]models.Comment, error)
 	//GetAllComments(memoID, parentID string) ([]models.Comment, error)
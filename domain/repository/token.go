@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/akinolaemmanuel49/memo-api/domain/models"
+
+type TokenRepository interface {
+	CreateRefreshToken(token *models.RefreshToken) (models.RefreshToken, error)
+	GetRefreshTokenByHash(tokenHash string) (models.RefreshToken, error)
+	RevokeRefreshTokenFamily(familyID string) error
+	RevokeUserRefreshTokens(userID string) error
+	CreatePasswordResetToken(token *models.PasswordResetToken) (models.PasswordResetToken, error)
+	GetPasswordResetTokenByHash(tokenHash string) (models.PasswordResetToken, error)
+	ResetPassword(id string, userID string, passwordHash string) error
+	CreatePersonalAccessToken(token *models.PersonalAccessToken) (models.PersonalAccessToken, error)
+	GetPersonalAccessTokenByHash(tokenHash string) (models.PersonalAccessToken, error)
+	GetPersonalAccessTokensByUserID(userID string) ([]models.PersonalAccessToken, error)
+	TouchPersonalAccessToken(id string) error
+	RevokePersonalAccessToken(id string, userID string) error
+}
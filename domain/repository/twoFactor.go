@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+type TwoFactorRepository interface {
+	Get(userID string) (models.TwoFactor, error)
+	SetPendingSecret(userID string, secret string) (models.TwoFactor, error)
+	Enable(userID string, recoveryCodeHashes []string) error
+	Disable(userID string) error
+	UseStep(userID string, step int64) error
+	ConsumeRecoveryCode(userID string, codeHash string) error
+	ReplaceRecoveryCodes(userID string, recoveryCodeHashes []string) error
+	GetUnsealedSecrets(sealedPrefix string, limit int) ([]models.TwoFactor, error)
+	ReplaceSecret(userID string, oldSecret string, newSecret string) error
+	UseChallenge(id string, userID string, expiresAt time.Time) error
+	DeleteExpiredChallenges(now time.Time) error
+}
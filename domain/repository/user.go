@@ -1,14 +1,26 @@
 package repository
 
-import "github.com/akinolaemmanuel49/memo-api/domain/models"
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
 
 type UserRepository interface {
 	Create(user *models.User) (models.User, error)
 	GetById(id string) (models.User, error)
 	GetByEmail(email string) (models.User, error)
+	GetByUsername(username string) (models.User, error)
 	GetAll(page, pageSize int) ([]models.User, error)
+	GetBySearchString(searchString string, page, pageSize int) ([]models.User, error)
 	GetFollowersOfUser(id string, page, pageSize int) ([]models.User, error)
 	GetUsersFollowedBy(id string, page, pageSize int) ([]models.User, error)
 	Update(id string, updatedUser models.User) (models.User, error)
-	Delete(id string, deletedUser models.User) (models.User, error)
+	Delete(id string, deletedUser models.User, purgeAfter time.Time) (models.User, error)
+	UpdatePasswordHash(id string, currentHash string, newHash string) error
+	CancelDeletion(id string) error
+	SchedulePurges(purgeAfter time.Time) (int64, error)
+	GetDueForPurge(before time.Time, limit int) ([]models.User, error)
+	GetMedia(id string) (models.UserMedia, error)
+	Purge(id string) error
 }
@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type dataExport struct {
+	Db *sql.DB
+}
+
+func NewDataExportInfrastructure(db *sql.DB) repository.DataExportRepository {
+	return dataExport{Db: db}
+}
+
+// data_exports table constraints
+const (
+	duplicatePendingExport = "data_exports_pending_user_id_key"
+)
+
+// Create stores a new pending export.
+// repository.ErrExportInProgress is returned if the user already has a pending export.
+func (d dataExport) Create(export *models.DataExport) (models.DataExport, error) {
+	query := `
+	INSERT INTO public.data_exports(user_id, token_hash, expires_at)
+	VALUES($1, $2, $3)
+	RETURNING id, status, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newExport := *export
+	err := d.Db.QueryRowContext(
+		ctx,
+		query,
+		export.UserID,
+		export.TokenHash,
+		export.ExpiresAt,
+	).Scan(&newExport.ID, &newExport.Status, &newExport.CreatedAt, &newExport.UpdatedAt)
+
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicatePendingExport):
+			return models.DataExport{}, repository.ErrExportInProgress
+
+		default:
+			return models.DataExport{}, err
+		}
+	}
+
+	return newExport, nil
+}
+
+// Get retrieves an export belonging to the user with matching userID.
+// repository.ErrRecordNotFound is returned if no export of the user matches the id.
+func (d dataExport) Get(id string, userID string) (models.DataExport, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		status,
+		token_hash,
+		file_path,
+		failure,
+		expires_at,
+		completed_at,
+		downloaded_at,
+		created_at,
+		updated_at,
+		_version
+	FROM public.data_exports
+	WHERE id = $1 AND user_id = $2
+	`
+
+	return d.getOne(query, id, userID)
+}
+
+// GetByTokenHash retrieves an export via the hash of its download token.
+// repository.ErrRecordNotFound is returned if no export matches the hash.
+func (d dataExport) GetByTokenHash(tokenHash string) (models.DataExport, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		status,
+		token_hash,
+		file_path,
+		failure,
+		expires_at,
+		completed_at,
+		downloaded_at,
+		created_at,
+		updated_at,
+		_version
+	FROM public.data_exports
+	WHERE token_hash = $1
+	`
+
+	return d.getOne(query, tokenHash)
+}
+
+func (d dataExport) getOne(query string, args ...any) (models.DataExport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	foundExport := models.DataExport{}
+	err := d.Db.QueryRowContext(ctx, query, args...).
+		Scan(
+			&foundExport.ID,
+			&foundExport.UserID,
+			&foundExport.Status,
+			&foundExport.TokenHash,
+			&foundExport.FilePath,
+			&foundExport.Failure,
+			&foundExport.ExpiresAt,
+			&foundExport.CompletedAt,
+			&foundExport.DownloadedAt,
+			&foundExport.CreatedAt,
+			&foundExport.UpdatedAt,
+			&foundExport.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.DataExport{}, repository.ErrRecordNotFound
+
+		default:
+			return models.DataExport{}, err
+		}
+	}
+
+	return foundExport, nil
+}
+
+// Complete marks a pending export as completed, downloadable from filePath until expiresAt.
+// repository.ErrRecordNotFound is returned if no pending export matches the id.
+func (d dataExport) Complete(id string, filePath string, expiresAt time.Time) error {
+	query := `
+	UPDATE public.data_exports
+	SET
+		status = 'completed',
+		file_path = $1,
+		expires_at = $2,
+		completed_at = $3,
+		updated_at = $3,
+		_version = _version + 1
+	WHERE id = $4 AND status = 'pending'
+	`
+
+	return d.update(query, filePath, expiresAt, time.Now().UTC(), id)
+}
+
+// Fail marks a pending export as failed for the given reason.
+// repository.ErrRecordNotFound is returned if no pending export matches the id.
+func (d dataExport) Fail(id string, failure string) error {
+	query := `
+	UPDATE public.data_exports
+	SET
+		status = 'failed',
+		failure = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE id = $3 AND status = 'pending'
+	`
+
+	return d.update(query, failure, time.Now().UTC(), id)
+}
+
+// MarkDownloaded records that a completed export was downloaded, which uses up its download link.
+// repository.ErrTokenReused is returned if the export was already downloaded.
+func (d dataExport) MarkDownloaded(id string) error {
+	query := `
+	UPDATE public.data_exports
+	SET
+		downloaded_at = $1,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE id = $2 AND status = 'completed' AND downloaded_at IS NULL
+	`
+
+	err := d.update(query, time.Now().UTC(), id)
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return repository.ErrTokenReused
+	}
+	return err
+}
+
+func (d dataExport) update(query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := d.Db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetExpired retrieves up to limit exports that expired before the given time, whatever their status.
+func (d dataExport) GetExpired(before time.Time, limit int) ([]models.DataExport, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		status,
+		token_hash,
+		file_path,
+		failure,
+		expires_at,
+		completed_at,
+		downloaded_at,
+		created_at,
+		updated_at,
+		_version
+	FROM public.data_exports
+	WHERE expires_at <= $1
+	ORDER BY expires_at
+	LIMIT $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := d.Db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	exports := make([]models.DataExport, 0)
+	for rows.Next() {
+		var export models.DataExport
+		err := rows.Scan(
+			&export.ID,
+			&export.UserID,
+			&export.Status,
+			&export.TokenHash,
+			&export.FilePath,
+			&export.Failure,
+			&export.ExpiresAt,
+			&export.CompletedAt,
+			&export.DownloadedAt,
+			&export.CreatedAt,
+			&export.UpdatedAt,
+			&export.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// Delete removes an export.
+func (d dataExport) Delete(id string) error {
+	query := `
+	DELETE FROM public.data_exports
+	WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := d.Db.ExecContext(ctx, query, id)
+	return err
+}
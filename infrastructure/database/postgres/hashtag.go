@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type hashtag struct {
+	Db *sql.DB
+}
+
+func NewHashtagInfrastructure(db *sql.DB) repository.HashtagRepository {
+	return hashtag{Db: db}
+}
+
+// Search returns up to limit hashtags starting with prefix, most used first.
+// Only the memos that would show on the hashtag's timeline for viewerID are counted,
+// so hashtags used only in memos the viewer may not see are left out.
+func (h hashtag) Search(prefix string, viewerID string, limit int) ([]models.Hashtag, error) {
+	query := `
+	SELECT
+		h.id,
+		h.name,
+		count(*) AS memo_count,
+		h.created_at
+	FROM public.hashtags h
+		JOIN public.memo_hashtags mh ON mh.hashtag_id = h.id
+		JOIN public.memos ON memos.id = mh.memo_id
+	WHERE h.name LIKE $1 || '%'
+		AND (memos.owner_id = $2 OR memos.visibility = 'public')` +
+		timelineCondition("$2") + `
+	GROUP BY h.id
+	ORDER BY memo_count DESC, h.name
+	LIMIT $3
+`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// escape the LIKE wildcards, as the underscore is allowed in hashtags
+	rows, err := h.Db.QueryContext(ctx, query, likeEscaper.Replace(prefix), viewerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	hashtags := make([]models.Hashtag, 0)
+	for rows.Next() {
+		var hashtag models.Hashtag
+		err := rows.Scan(
+			&hashtag.ID,
+			&hashtag.Name,
+			&hashtag.MemoCount,
+			&hashtag.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		hashtags = append(hashtags, hashtag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hashtags, nil
+}
+
+// GetMemos returns the memos tagged with the hashtag name that viewerID is allowed to see, newest first.
+// Unlisted, followers-only and private memos are left out, except for the viewer's own.
+func (h hashtag) GetMemos(name string, viewerID string, page, pageSize int) ([]models.Memo, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		memo_content,
+		memo_type,
+		likes,
+		shares,
+		caption,
+		transcript,
+		audience,
+		visibility,
+		deleted,
+		created_at,
+		updated_at,
+		edited_at,
+		owner_id,
+		_version
+	FROM public.memos
+	WHERE memos.id IN (
+			SELECT mh.memo_id FROM public.memo_hashtags mh
+				JOIN public.hashtags h ON h.id = mh.hashtag_id
+			WHERE h.name = $1)
+		AND (memos.owner_id = $2 OR memos.visibility = 'public')` +
+		timelineCondition("$2") + `
+	ORDER BY created_at DESC
+	LIMIT $3 OFFSET $4
+`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := h.Db.QueryContext(ctx, query, name, viewerID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	memos := make([]models.Memo, 0)
+	for rows.Next() {
+		var memo models.Memo
+		err := rows.Scan(
+			&memo.ID,
+			&memo.Content,
+			&memo.MemoType,
+			&memo.Likes,
+			&memo.Shares,
+			&memo.Caption,
+			&memo.Transcript,
+			&memo.Audience,
+			&memo.Visibility,
+			&memo.Deleted,
+			&memo.CreatedAt,
+			&memo.UpdatedAt,
+			&memo.EditedAt,
+			&memo.OwnerID,
+			&memo.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		memos = append(memos, memo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return memos, nil
+}
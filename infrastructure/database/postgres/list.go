@@ -0,0 +1,537 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type list struct {
+	Db *sql.DB
+}
+
+func NewListInfrastructure(db *sql.DB) repository.ListRepository {
+	return list{Db: db}
+}
+
+const (
+	duplicateListOwnerName = "lists_owner_id_name_key"
+	duplicateListMember    = "list_members_pkey"
+	duplicateListFollower  = "list_followers_pkey"
+)
+
+// listColumns are the columns of a list, along with the number of its members and followers.
+const listColumns = `
+		l.id,
+		l.owner_id,
+		l.name,
+		l.description,
+		l.private,
+		(SELECT count(*) FROM public.list_members lm WHERE lm.list_id = l.id),
+		(SELECT count(*) FROM public.list_followers lf WHERE lf.list_id = l.id),
+		l.created_at,
+		l.updated_at,
+		l._version`
+
+// Create creates a new list for its owner.
+// repository.ErrDuplicateList is returned if the owner already has a list of the same name, regardless of case.
+func (l list) Create(list *models.List) (models.List, error) {
+	query := `
+	INSERT INTO public.lists(owner_id, name, description, private)
+	VALUES($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at, _version
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newList := *list
+	err := l.Db.QueryRowContext(
+		ctx,
+		query,
+		list.OwnerID,
+		list.Name,
+		list.Description,
+		list.Private,
+	).Scan(&newList.ID, &newList.CreatedAt, &newList.UpdatedAt, &newList.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateListOwnerName):
+			return models.List{}, repository.ErrDuplicateList
+		default:
+			return models.List{}, err
+		}
+	}
+
+	return newList, nil
+}
+
+// Get retrieves a list via its ID.
+// repository.ErrRecordNotFound is returned if no list matches the id.
+func (l list) Get(id string) (models.List, error) {
+	query := `
+	SELECT` + listColumns + `
+	FROM public.lists l
+	WHERE l.id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	foundList := models.List{}
+	err := l.Db.QueryRowContext(ctx, query, id).
+		Scan(
+			&foundList.ID,
+			&foundList.OwnerID,
+			&foundList.Name,
+			&foundList.Description,
+			&foundList.Private,
+			&foundList.MemberCount,
+			&foundList.FollowerCount,
+			&foundList.CreatedAt,
+			&foundList.UpdatedAt,
+			&foundList.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.List{}, repository.ErrRecordNotFound
+
+		default:
+			return models.List{}, err
+		}
+	}
+
+	return foundList, nil
+}
+
+// GetByOwnerID retrieves the lists of a user, most recently created first.
+// Private lists are left out unless includePrivate is set.
+func (l list) GetByOwnerID(ownerID string, includePrivate bool, page, pageSize int) ([]models.List, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT` + listColumns + `
+	FROM public.lists l
+	WHERE l.owner_id = $1 AND ($2 OR l.private = FALSE)
+	ORDER BY l.created_at DESC
+	LIMIT $3 OFFSET $4
+	`
+
+	return l.getMany(query, ownerID, includePrivate, pageSize, offset)
+}
+
+// GetFollowedByUser retrieves the public lists followed by a user, most recently followed first.
+func (l list) GetFollowedByUser(userID string, page, pageSize int) ([]models.List, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT` + listColumns + `
+	FROM public.lists l
+	JOIN public.list_followers f ON f.list_id = l.id
+	WHERE f.user_id = $1 AND l.private = FALSE
+	ORDER BY f.created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	return l.getMany(query, userID, pageSize, offset)
+}
+
+func (l list) getMany(query string, args ...any) ([]models.List, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := l.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	lists := make([]models.List, 0)
+	for rows.Next() {
+		var foundList models.List
+		err := rows.Scan(
+			&foundList.ID,
+			&foundList.OwnerID,
+			&foundList.Name,
+			&foundList.Description,
+			&foundList.Private,
+			&foundList.MemberCount,
+			&foundList.FollowerCount,
+			&foundList.CreatedAt,
+			&foundList.UpdatedAt,
+			&foundList.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, foundList)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// Update updates the name, description and privacy of a list. Followers of a list made private are removed.
+// repository.ErrRecordNotFound is returned if no list matches the id.
+// repository.ErrDuplicateList is returned if the owner already has another list of the new name.
+// repository.ErrConcurrentUpdate is returned if the list has been updated in the meantime.
+func (l list) Update(id string, updatedList models.List) (models.List, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	selectQuery := `SELECT _version from public.lists WHERE id = $1 FOR NO KEY UPDATE;`
+	updateQuery := `
+	UPDATE public.lists
+		SET
+		    name = $1,
+		    description = $2,
+		    private = $3,
+		    updated_at = $4,
+		    _version = _version + 1
+		WHERE id = $5 AND _version = $6;`
+	deleteFollowersQuery := `DELETE FROM public.list_followers WHERE list_id = $1;`
+
+	tx, err := l.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	var currentVersion int
+	// Fetch current version value from list instance
+	err = tx.QueryRowContext(ctx, selectQuery, id).Scan(&currentVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.List{}, repository.ErrRecordNotFound
+		default:
+			return models.List{}, err
+		}
+	}
+	// Check versions
+	if currentVersion != updatedList.Version {
+		return models.List{}, repository.ErrConcurrentUpdate
+	}
+
+	// Update list instance
+	_, err = tx.ExecContext(ctx,
+		updateQuery,
+		updatedList.Name,
+		updatedList.Description,
+		updatedList.Private,
+		time.Now().UTC(),
+		id,
+		updatedList.Version)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateListOwnerName):
+			return models.List{}, repository.ErrDuplicateList
+		default:
+			return models.List{}, err
+		}
+	}
+
+	// private lists cannot be followed
+	if updatedList.Private {
+		if _, err := tx.ExecContext(ctx, deleteFollowersQuery, id); err != nil {
+			return models.List{}, err
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return models.List{}, err
+	}
+
+	return l.Get(id)
+}
+
+// Delete removes a list along with its members and followers.
+// repository.ErrRecordNotFound is returned if no list matches the id.
+func (l list) Delete(id string) error {
+	query := `
+	DELETE FROM public.lists
+	WHERE id = $1
+	`
+
+	return l.exec(query, id)
+}
+
+// AddMember adds a user to a list.
+// repository.ErrDuplicateListMember is returned if the user is already a member of the list.
+func (l list) AddMember(listID, userID string) error {
+	query := `
+	INSERT INTO public.list_members(list_id, user_id)
+	VALUES($1, $2)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := l.Db.ExecContext(ctx, query, listID, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateListMember):
+			return repository.ErrDuplicateListMember
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from a list.
+// repository.ErrRecordNotFound is returned if the user is not a member of the list.
+func (l list) RemoveMember(listID, userID string) error {
+	query := `
+	DELETE FROM public.list_members
+	WHERE list_id = $1 AND user_id = $2
+	`
+
+	return l.exec(query, listID, userID)
+}
+
+// GetMembers retrieves the members of a list, most recently added first, leaving out deleted users.
+func (l list) GetMembers(listID string, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.created_at,
+		u.updated_at
+	FROM public.users u
+	JOIN public.list_members m ON u.id = m.user_id
+	WHERE m.list_id = $1 AND u.deleted = FALSE
+	ORDER BY m.created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := l.Db.QueryContext(ctx, query, listID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.AvatarURL,
+			&user.Status,
+			&user.About,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&user.Private,
+			&user.Deleted,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Follow makes a list part of the lists followed by a user.
+// repository.ErrDuplicateListFollow is returned if the user already follows the list.
+func (l list) Follow(listID, userID string) error {
+	query := `
+	INSERT INTO public.list_followers(list_id, user_id)
+	VALUES($1, $2)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := l.Db.ExecContext(ctx, query, listID, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateListFollower):
+			return repository.ErrDuplicateListFollow
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Unfollow removes a list from the lists followed by a user.
+// repository.ErrRecordNotFound is returned if the user does not follow the list.
+func (l list) Unfollow(listID, userID string) error {
+	query := `
+	DELETE FROM public.list_followers
+	WHERE list_id = $1 AND user_id = $2
+	`
+
+	return l.exec(query, listID, userID)
+}
+
+func (l list) exec(query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := l.Db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetFeed fetches the memos of the members of a list as seen by the viewer, leaving out private users the viewer
+// does not follow, users blocking or blocked by the viewer, memos the viewer may not see by their visibility,
+// memos for close friends the viewer is not one of and memos hidden by the viewer's mutes.
+func (l list) GetFeed(listID, viewerID string, page, pageSize int) ([]models.Memo, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	// Query for all posts made by the members of the list.
+	query := `
+	SELECT
+		id,
+		memo_content,
+		memo_type,
+		likes,
+		shares,
+		caption,
+		transcript,
+		audience,
+		visibility,
+		deleted,
+		created_at,
+		updated_at,
+		edited_at,
+		owner_id,
+		_version
+	FROM public.memos
+	WHERE owner_id IN (
+		SELECT user_id
+		FROM public.list_members
+		WHERE list_id = $1)` +
+		visibleCondition("$2") +
+		timelineCondition("$2") + `
+	ORDER BY created_at DESC
+	LIMIT $3 OFFSET $4
+`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := l.Db.QueryContext(ctx, query, listID, viewerID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	memos := make([]models.Memo, 0)
+	for rows.Next() {
+		var memo models.Memo
+		err := rows.Scan(
+			&memo.ID,
+			&memo.Content,
+			&memo.MemoType,
+			&memo.Likes,
+			&memo.Shares,
+			&memo.Caption,
+			&memo.Transcript,
+			&memo.Audience,
+			&memo.Visibility,
+			&memo.Deleted,
+			&memo.CreatedAt,
+			&memo.UpdatedAt,
+			&memo.EditedAt,
+			&memo.OwnerID,
+			&memo.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		memos = append(memos, memo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return memos, nil
+}
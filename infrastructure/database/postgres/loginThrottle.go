@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type loginThrottle struct {
+	Db *sql.DB
+}
+
+func NewLoginThrottleInfrastructure(db *sql.DB) repository.LoginThrottleRepository {
+	return loginThrottle{Db: db}
+}
+
+// GetFailure retrieves the failed login count of an account or IP address.
+// repository.ErrRecordNotFound is returned if no failed login has been recorded.
+func (l loginThrottle) GetFailure(scope, subject string) (models.LoginFailure, error) {
+	query := `
+	SELECT
+		scope,
+		subject,
+		failures,
+		last_failed_at,
+		locked_until,
+		created_at,
+		updated_at,
+		_version
+	FROM public.login_failures
+	WHERE scope = $1 AND subject = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	foundFailure := models.LoginFailure{}
+	err := l.Db.QueryRowContext(ctx, query, scope, subject).
+		Scan(
+			&foundFailure.Scope,
+			&foundFailure.Subject,
+			&foundFailure.Failures,
+			&foundFailure.LastFailedAt,
+			&foundFailure.LockedUntil,
+			&foundFailure.CreatedAt,
+			&foundFailure.UpdatedAt,
+			&foundFailure.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.LoginFailure{}, repository.ErrRecordNotFound
+
+		default:
+			return models.LoginFailure{}, err
+		}
+	}
+
+	return foundFailure, nil
+}
+
+// RecordFailure counts a failed login against an account or IP address and returns the updated count.
+// The count starts over when the previous failure is older than window.
+func (l loginThrottle) RecordFailure(scope, subject string, window time.Duration) (models.LoginFailure, error) {
+	query := `
+	INSERT INTO public.login_failures(scope, subject, failures, last_failed_at)
+	VALUES($1, $2, 1, $3)
+	ON CONFLICT (scope, subject) DO UPDATE
+	SET
+		failures = CASE
+			WHEN login_failures.last_failed_at < $4 THEN 1
+			ELSE login_failures.failures + 1
+		END,
+		last_failed_at = $3,
+		updated_at = $3,
+		_version = login_failures._version + 1
+	RETURNING scope, subject, failures, last_failed_at, locked_until, created_at, updated_at, _version
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	now := time.Now().UTC()
+	failure := models.LoginFailure{}
+	err := l.Db.QueryRowContext(ctx, query, scope, subject, now, now.Add(-window)).
+		Scan(
+			&failure.Scope,
+			&failure.Subject,
+			&failure.Failures,
+			&failure.LastFailedAt,
+			&failure.LockedUntil,
+			&failure.CreatedAt,
+			&failure.UpdatedAt,
+			&failure.Version,
+		)
+
+	if err != nil {
+		switch {
+		default:
+			return models.LoginFailure{}, err
+		}
+	}
+
+	return failure, nil
+}
+
+// ResetFailures clears the failed login count of an account or IP address.
+func (l loginThrottle) ResetFailures(scope, subject string) error {
+	query := `
+	DELETE FROM public.login_failures
+	WHERE scope = $1 AND subject = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := l.Db.ExecContext(ctx, query, scope, subject)
+	return err
+}
+
+// Lock locks an account or IP address out until lockout.LockedUntil and keeps a record of the lockout.
+func (l loginThrottle) Lock(lockout *models.Lockout) (models.Lockout, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	lockQuery := `
+	UPDATE public.login_failures
+	SET
+		locked_until = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE scope = $3 AND subject = $4;`
+	recordQuery := `
+	INSERT INTO public.lockouts(scope, subject, ip_address, failures, locked_until)
+	VALUES($1, $2, $3, $4, $5)
+	RETURNING id, created_at, updated_at;`
+
+	tx, err := l.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Lockout{}, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	_, err = tx.ExecContext(ctx, lockQuery, lockout.LockedUntil, time.Now().UTC(), lockout.Scope, lockout.Subject)
+	if err != nil {
+		return models.Lockout{}, err
+	}
+
+	newLockout := *lockout
+	err = tx.QueryRowContext(
+		ctx,
+		recordQuery,
+		lockout.Scope,
+		lockout.Subject,
+		lockout.IPAddress,
+		lockout.Failures,
+		lockout.LockedUntil,
+	).Scan(&newLockout.ID, &newLockout.CreatedAt, &newLockout.UpdatedAt)
+	if err != nil {
+		return models.Lockout{}, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return models.Lockout{}, err
+	}
+
+	return newLockout, nil
+}
+
+// GetLockouts retrieves a list of lockouts, most recent first.
+func (l loginThrottle) GetLockouts(page, pageSize int) ([]models.Lockout, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		scope,
+		subject,
+		ip_address,
+		failures,
+		locked_until,
+		created_at,
+		updated_at,
+		_version
+	FROM public.lockouts
+	ORDER BY created_at DESC
+	LIMIT $1 OFFSET $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := l.Db.QueryContext(ctx, query, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	lockouts := make([]models.Lockout, 0)
+	for rows.Next() {
+		var lockout models.Lockout
+		err := rows.Scan(
+			&lockout.ID,
+			&lockout.Scope,
+			&lockout.Subject,
+			&lockout.IPAddress,
+			&lockout.Failures,
+			&lockout.LockedUntil,
+			&lockout.CreatedAt,
+			&lockout.UpdatedAt,
+			&lockout.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lockouts = append(lockouts, lockout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lockouts, nil
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/akinolaemmanuel49/memo-api/domain/models"
 	"github.com/akinolaemmanuel49/memo-api/domain/repository"
 	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
@@ -19,20 +21,82 @@ func NewMemoInfrastructure(db *sql.DB) repository.MemoRepository {
 	return memo{Db: db}
 }
 
-// CreateMemo creates and returns an instance of a new text memo,
+// memoTextExpression is the text of a memo matched against muted words. Media memos are matched by caption and transcript.
+const memoTextExpression = "concat_ws(' ', CASE WHEN memos.memo_type = 'text' THEN memos.memo_content END, memos.caption, memos.transcript)"
+
+// visibleCondition returns an SQL condition leaving out the memos the viewer bound to viewerParam may not see
+// because of their visibility: private memos of other users and followers-only memos of users the viewer does not follow.
+func visibleCondition(viewerParam string) string {
+	return `
+		AND (memos.owner_id = ` + viewerParam + `
+			OR memos.visibility IN ('public', 'unlisted')
+			OR (memos.visibility = 'followers' AND EXISTS (
+				SELECT 1 FROM public.follow vf
+				WHERE vf.follower_id = ` + viewerParam + ` AND vf.subject_id = memos.owner_id)))`
+}
+
+// ownerNotSuspendedCondition is an SQL condition leaving out the memos of suspended users.
+const ownerNotSuspendedCondition = `
+		AND NOT EXISTS (SELECT 1 FROM public.users su WHERE su.id = memos.owner_id AND su.suspended = TRUE)`
+
+// timelineCondition returns an SQL condition leaving out the memos that do not belong in the timelines
+// of the viewer bound to viewerParam: deleted and suspended memos, memos of suspended users,
+// memos of private users the viewer does not follow,
+// memos of users blocking or blocked by the viewer, memos for close friends the viewer is not one of
+// and memos hidden by the viewer's mutes. Memos are not filtered by their visibility, which differs between timelines.
+func timelineCondition(viewerParam string) string {
+	return `
+		AND memos.suspended = FALSE
+		AND memos.deleted = FALSE
+		AND (memos.owner_id = ` + viewerParam + `
+			OR EXISTS (SELECT 1 FROM public.users ou WHERE ou.id = memos.owner_id AND ou.private = FALSE)
+			OR EXISTS (SELECT 1 FROM public.follow f WHERE f.follower_id = ` + viewerParam + ` AND f.subject_id = memos.owner_id))
+		AND NOT EXISTS (
+			SELECT 1 FROM public.blocks b
+			WHERE (b.blocker_id = ` + viewerParam + ` AND b.blocked_id = memos.owner_id)
+				OR (b.blocker_id = memos.owner_id AND b.blocked_id = ` + viewerParam + `))` +
+		ownerNotSuspendedCondition +
+		inAudienceCondition(viewerParam) +
+		notMutedCondition(viewerParam, "memos.owner_id", memoTextExpression)
+}
+
+// inAudienceCondition returns an SQL condition leaving out memos shared with close friends only,
+// unless the viewer bound to viewerParam owns them or is one of the close friends of their owner.
+func inAudienceCondition(viewerParam string) string {
+	return `
+		AND (memos.audience = 'everyone'
+			OR memos.owner_id = ` + viewerParam + `
+			OR EXISTS (
+				SELECT 1 FROM public.close_friends cf
+				WHERE cf.user_id = memos.owner_id AND cf.friend_id = ` + viewerParam + `))`
+}
+
+// CreateMemo creates and returns an instance of a new text memo, indexing its hashtags,
 // it returns an error if ownerID is not set.
 func (m memo) CreateMemo(ownerID string, memo *models.Memo) (models.Memo, error) {
 	query := `
-	INSERT INTO public.memos(memo_content, owner_id, memo_type, caption, transcript)
-	VALUES($1, $2, $3, $4, $5)
+	INSERT INTO public.memos(memo_content, owner_id, memo_type, caption, transcript, audience, visibility)
+	VALUES($1, $2, $3, $4, $5, $6, $7)
 	RETURNING id, created_at, updated_at
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.UploadTimeoutDuration)
 	defer cancel()
 
+	tx, err := m.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Memo{}, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
 	newMemo := *memo
-	err := m.Db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		memo.Content,
@@ -40,6 +104,8 @@ func (m memo) CreateMemo(ownerID string, memo *models.Memo) (models.Memo, error)
 		memo.MemoType,
 		memo.Caption,
 		memo.Transcript,
+		memo.Audience,
+		memo.Visibility,
 	).Scan(&newMemo.ID, &newMemo.CreatedAt, &newMemo.UpdatedAt)
 
 	if err != nil {
@@ -49,9 +115,49 @@ func (m memo) CreateMemo(ownerID string, memo *models.Memo) (models.Memo, error)
 		}
 	}
 
+	if err := indexHashtags(ctx, tx, newMemo); err != nil {
+		return models.Memo{}, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return models.Memo{}, err
+	}
+
 	return newMemo, nil
 }
 
+// indexHashtags replaces the hashtags indexed for a memo with the hashtags in its text, for text memos,
+// or in its caption, for media memos.
+func indexHashtags(ctx context.Context, tx *sql.Tx, memo models.Memo) error {
+	deleteQuery := `DELETE FROM public.memo_hashtags WHERE memo_id = $1;`
+	insertQuery := `
+	WITH tags AS (
+		INSERT INTO public.hashtags(name)
+		SELECT unnest($2::text[])
+		ON CONFLICT ON CONSTRAINT unique_hashtags_name DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	)
+	INSERT INTO public.memo_hashtags(memo_id, hashtag_id)
+	SELECT $1::uuid, id FROM tags;`
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, memo.ID); err != nil {
+		return err
+	}
+
+	text := memo.Caption
+	if memo.MemoType == "text" {
+		text = memo.Content
+	}
+	hashtags := helpers.ExtractHashtags(text)
+	if len(hashtags) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, insertQuery, memo.ID, pq.Array(hashtags))
+	return err
+}
+
 // GetMemo retrieves an existing memo via its ID.
 // repository.ErrRecordNotFound is returned if no text memo matches the query.
 func (m memo) GetMemo(id string) (models.Memo, error) {
@@ -64,9 +170,13 @@ func (m memo) GetMemo(id string) (models.Memo, error) {
 		shares,
 		caption,
 		transcript,
+		audience,
+		visibility,
 		deleted,
+		suspended,
 		created_at,
 		updated_at,
+		edited_at,
 		owner_id,
 		_version
 	FROM public.memos
@@ -86,9 +196,13 @@ func (m memo) GetMemo(id string) (models.Memo, error) {
 			&foundMemo.Shares,
 			&foundMemo.Caption,
 			&foundMemo.Transcript,
+			&foundMemo.Audience,
+			&foundMemo.Visibility,
 			&foundMemo.Deleted,
+			&foundMemo.Suspended,
 			&foundMemo.CreatedAt,
 			&foundMemo.UpdatedAt,
+			&foundMemo.EditedAt,
 			&foundMemo.OwnerID,
 			&foundMemo.Version,
 		)
@@ -107,11 +221,18 @@ func (m memo) GetMemo(id string) (models.Memo, error) {
 		return foundMemo, repository.ErrRecordDeleted
 	}
 
+	// suspended memos are hidden from everyone but moderators
+	if foundMemo.Suspended {
+		return models.Memo{}, repository.ErrRecordNotFound
+	}
+
 	return foundMemo, nil
 }
 
-// GetMemosByFollowing fetches all memo instances from all users.
-func (m memo) GetAllMemos(page, pageSize int) ([]models.Memo, error) {
+// GetAllMemos fetches the public memos of all users along with the viewer's own memos, leaving out deleted memos,
+// private users the viewer does not follow, users blocking or blocked by the viewer,
+// memos for close friends the viewer is not one of and memos hidden by the viewer's mutes.
+func (m memo) GetAllMemos(viewerID string, page, pageSize int) ([]models.Memo, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -128,11 +249,17 @@ func (m memo) GetAllMemos(page, pageSize int) ([]models.Memo, error) {
 		shares,
 		caption,
 		transcript,
+		audience,
+		visibility,
 		deleted,
 		created_at,
 		updated_at,
-		owner_id
+		edited_at,
+		owner_id,
+		_version
 	FROM public.memos
+	WHERE (memos.owner_id = $3 OR memos.visibility = 'public')` +
+		timelineCondition("$3") + `
 	ORDER BY created_at DESC
 	LIMIT $1 OFFSET $2
 `
@@ -140,7 +267,7 @@ func (m memo) GetAllMemos(page, pageSize int) ([]models.Memo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
-	rows, err := m.Db.QueryContext(ctx, query, pageSize, offset)
+	rows, err := m.Db.QueryContext(ctx, query, pageSize, offset, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -162,10 +289,14 @@ func (m memo) GetAllMemos(page, pageSize int) ([]models.Memo, error) {
 			&memo.Shares,
 			&memo.Caption,
 			&memo.Transcript,
+			&memo.Audience,
+			&memo.Visibility,
 			&memo.Deleted,
 			&memo.CreatedAt,
 			&memo.UpdatedAt,
+			&memo.EditedAt,
 			&memo.OwnerID,
+			&memo.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -179,7 +310,8 @@ func (m memo) GetAllMemos(page, pageSize int) ([]models.Memo, error) {
 	return memos, nil
 }
 
-// GetMemosByFollowing fetches all memo instances from followed users.
+// GetMemosByFollowing fetches all memo instances from followed users, leaving out deleted and private memos,
+// memos for close friends the user is not one of and memos hidden by the user's mutes.
 func (m memo) GetMemosByFollowing(userID string, page, pageSize int) ([]models.Memo, error) {
 	if page < 1 {
 		page = 1
@@ -197,16 +329,22 @@ func (m memo) GetMemosByFollowing(userID string, page, pageSize int) ([]models.M
 		shares,
 		caption,
 		transcript,
+		audience,
+		visibility,
 		deleted,
 		created_at,
 		updated_at,
-		owner_id
+		edited_at,
+		owner_id,
+		_version
 	FROM public.memos
-	WHERE owner_id IN (
+	WHERE (owner_id IN (
 		SELECT subject_id::uuid
 		FROM public.follow
 		WHERE follower_id = $1)
-		OR owner_id = $1
+		OR owner_id = $1)` +
+		visibleCondition("$1") +
+		timelineCondition("$1") + `
 	ORDER BY created_at DESC
 	LIMIT $2 OFFSET $3
 `
@@ -236,10 +374,14 @@ func (m memo) GetMemosByFollowing(userID string, page, pageSize int) ([]models.M
 			&memo.Shares,
 			&memo.Caption,
 			&memo.Transcript,
+			&memo.Audience,
+			&memo.Visibility,
 			&memo.Deleted,
 			&memo.CreatedAt,
 			&memo.UpdatedAt,
+			&memo.EditedAt,
 			&memo.OwnerID,
+			&memo.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -405,6 +547,10 @@ func (m memo) updateShareCount(memoID string, value int) error {
 	return nil
 }
 
+// Update updates the content, caption, transcript and visibility of a memo and reindexes its hashtags,
+// without recording a revision.
+// repository.ErrRecordNotFound is returned if no memo matches the id.
+// repository.ErrConcurrentUpdate is returned if the memo has been updated in the meantime.
 func (m memo) Update(id string, updatedMemo models.Memo) (models.Memo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
@@ -415,9 +561,12 @@ func (m memo) Update(id string, updatedMemo models.Memo) (models.Memo, error) {
 	UPDATE public.memos
 		SET
 		    memo_content = $1,
-		    updated_at = $2,
+		    caption = $2,
+		    transcript = $3,
+		    visibility = $4,
+		    updated_at = $5,
 		    _version = _version + 1
-		WHERE id = $3 AND _version=$4;`
+		WHERE id = $6 AND _version=$7;`
 
 	tx, err := m.Db.BeginTx(ctx, nil)
 	if err != nil {
@@ -451,6 +600,9 @@ func (m memo) Update(id string, updatedMemo models.Memo) (models.Memo, error) {
 	_, err = tx.ExecContext(ctx,
 		updateQuery,
 		updatedMemo.Content,
+		updatedMemo.Caption,
+		updatedMemo.Transcript,
+		updatedMemo.Visibility,
 		time.Now().UTC(),
 		id,
 		updatedMemo.Version)
@@ -461,6 +613,10 @@ func (m memo) Update(id string, updatedMemo models.Memo) (models.Memo, error) {
 			return models.Memo{}, err
 		}
 	}
+	updatedMemo.ID = id
+	if err := indexHashtags(ctx, tx, updatedMemo); err != nil {
+		return models.Memo{}, err
+	}
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -474,6 +630,150 @@ func (m memo) Update(id string, updatedMemo models.Memo) (models.Memo, error) {
 	return updatedMemo, nil
 }
 
+// Edit changes the content, caption and transcript of a memo, marks it as edited and reindexes its hashtags,
+// recording the memo as it was before the edit as one of its revisions.
+// repository.ErrRecordNotFound is returned if no memo that is not deleted matches the id.
+// repository.ErrConcurrentUpdate is returned if editedMemo is not the current version of the memo.
+func (m memo) Edit(id string, editedMemo models.Memo) (models.Memo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	selectQuery := `SELECT _version from public.memos WHERE id = $1 AND deleted = FALSE FOR NO KEY UPDATE;`
+	revisionQuery := `
+	INSERT INTO public.memo_revisions(memo_id, memo_version, memo_content, caption, transcript)
+	SELECT id, _version, COALESCE(memo_content, ''), COALESCE(caption, ''), COALESCE(transcript, '')
+	FROM public.memos
+	WHERE id = $1;`
+	editQuery := `
+	UPDATE public.memos
+		SET
+		    memo_content = $1,
+		    caption = $2,
+		    transcript = $3,
+		    edited_at = $4,
+		    updated_at = $4,
+		    _version = _version + 1
+		WHERE id = $5 AND _version = $6;`
+
+	tx, err := m.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Memo{}, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	var currentVersion int
+	// Fetch current version value from memo instance
+	err = tx.QueryRowContext(ctx, selectQuery, id).Scan(&currentVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.Memo{}, repository.ErrRecordNotFound
+		default:
+			return models.Memo{}, err
+		}
+	}
+	// Check versions
+	if currentVersion != editedMemo.Version {
+		return models.Memo{}, repository.ErrConcurrentUpdate
+	}
+
+	// Record the memo as it is before editing it
+	if _, err := tx.ExecContext(ctx, revisionQuery, id); err != nil {
+		return models.Memo{}, err
+	}
+	_, err = tx.ExecContext(ctx,
+		editQuery,
+		editedMemo.Content,
+		editedMemo.Caption,
+		editedMemo.Transcript,
+		time.Now().UTC(),
+		id,
+		editedMemo.Version)
+	if err != nil {
+		return models.Memo{}, err
+	}
+	editedMemo.ID = id
+	if err := indexHashtags(ctx, tx, editedMemo); err != nil {
+		return models.Memo{}, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return models.Memo{}, err
+	}
+
+	return m.GetMemo(id)
+}
+
+// GetRevisions retrieves the revisions of a memo, most recent first.
+func (m memo) GetRevisions(memoID string, page, pageSize int) ([]models.MemoRevision, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		memo_id,
+		memo_version,
+		memo_content,
+		caption,
+		transcript,
+		created_at
+	FROM public.memo_revisions
+	WHERE memo_id = $1
+	ORDER BY memo_version DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := m.Db.QueryContext(ctx, query, memoID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	revisions := make([]models.MemoRevision, 0)
+	for rows.Next() {
+		var revision models.MemoRevision
+		err := rows.Scan(
+			&revision.ID,
+			&revision.MemoID,
+			&revision.Version,
+			&revision.Content,
+			&revision.Caption,
+			&revision.Transcript,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Delete marks a memo as deleted and removes it from the hashtag index.
 func (m memo) Delete(id string, deletedMemo models.Memo) (models.Memo, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
@@ -487,6 +787,7 @@ func (m memo) Delete(id string, deletedMemo models.Memo) (models.Memo, error) {
 		    updated_at = $1,
 		    _version = _version + 1
 		WHERE id = $2 AND _version=$3;`
+	unindexQuery := `DELETE FROM public.memo_hashtags WHERE memo_id = $1;`
 
 	tx, err := m.Db.BeginTx(ctx, nil)
 	if err != nil {
@@ -531,6 +832,10 @@ func (m memo) Delete(id string, deletedMemo models.Memo) (models.Memo, error) {
 			return models.Memo{}, err
 		}
 	}
+	// Deleted memos are left out of hashtag timelines and searches
+	if _, err := tx.ExecContext(ctx, unindexQuery, id); err != nil {
+		return models.Memo{}, err
+	}
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -540,7 +845,10 @@ func (m memo) Delete(id string, deletedMemo models.Memo) (models.Memo, error) {
 	return deletedMemo, nil
 }
 
-func (m memo) GetMemosByOwnerID(ownerID string, page, pageSize int) ([]models.Memo, error) {
+// GetMemosByOwnerID fetches all memo instances made by a user that are not deleted.
+// No memos are returned if the owner and the viewer have blocked one another, private memos are left out
+// unless the viewer is the owner, and followers-only memos and memos for close friends unless the viewer is one of them.
+func (m memo) GetMemosByOwnerID(ownerID string, viewerID string, page, pageSize int) ([]models.Memo, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -557,12 +865,23 @@ func (m memo) GetMemosByOwnerID(ownerID string, page, pageSize int) ([]models.Me
 		shares,
 		caption,
 		transcript,
+		audience,
+		visibility,
 		deleted,
 		created_at,
 		updated_at,
-		owner_id
+		edited_at,
+		owner_id,
+		_version
 	FROM public.memos
-	WHERE owner_id = $1
+	WHERE owner_id = $1 AND suspended = FALSE AND deleted = FALSE
+		AND NOT EXISTS (
+			SELECT 1 FROM public.blocks b
+			WHERE (b.blocker_id = $4 AND b.blocked_id = memos.owner_id)
+				OR (b.blocker_id = memos.owner_id AND b.blocked_id = $4))` +
+		ownerNotSuspendedCondition +
+		visibleCondition("$4") +
+		inAudienceCondition("$4") + `
 	ORDER BY created_at DESC
 	LIMIT $2 OFFSET $3
 `
@@ -570,7 +889,7 @@ func (m memo) GetMemosByOwnerID(ownerID string, page, pageSize int) ([]models.Me
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
-	rows, err := m.Db.QueryContext(ctx, query, ownerID, pageSize, offset)
+	rows, err := m.Db.QueryContext(ctx, query, ownerID, pageSize, offset, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -592,10 +911,14 @@ func (m memo) GetMemosByOwnerID(ownerID string, page, pageSize int) ([]models.Me
 			&memo.Shares,
 			&memo.Caption,
 			&memo.Transcript,
+			&memo.Audience,
+			&memo.Visibility,
 			&memo.Deleted,
 			&memo.CreatedAt,
 			&memo.UpdatedAt,
+			&memo.EditedAt,
 			&memo.OwnerID,
+			&memo.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -608,3 +931,121 @@ func (m memo) GetMemosByOwnerID(ownerID string, page, pageSize int) ([]models.Me
 
 	return memos, nil
 }
+
+// GetLikesByUserID retrieves the likes of a user, most recent first.
+func (m memo) GetLikesByUserID(userID string, page, pageSize int) ([]models.Like, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		memo_id,
+		liked_by,
+		created_at,
+		updated_at,
+		_version
+	FROM public.likes
+	WHERE liked_by = $1
+	ORDER BY created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := m.Db.QueryContext(ctx, query, userID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	likes := make([]models.Like, 0)
+	for rows.Next() {
+		var like models.Like
+		err := rows.Scan(
+			&like.ID,
+			&like.MemoID,
+			&like.LikedBy,
+			&like.CreatedAt,
+			&like.UpdatedAt,
+			&like.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		likes = append(likes, like)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return likes, nil
+}
+
+// GetSharesByUserID retrieves the shares of a user, most recent first.
+func (m memo) GetSharesByUserID(userID string, page, pageSize int) ([]models.Share, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		memo_id,
+		shared_by,
+		created_at,
+		updated_at,
+		_version
+	FROM public.shares
+	WHERE shared_by = $1
+	ORDER BY created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := m.Db.QueryContext(ctx, query, userID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	shares := make([]models.Share, 0)
+	for rows.Next() {
+		var share models.Share
+		err := rows.Scan(
+			&share.ID,
+			&share.MemoID,
+			&share.SharedBy,
+			&share.CreatedAt,
+			&share.UpdatedAt,
+			&share.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
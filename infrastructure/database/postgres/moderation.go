@@ -0,0 +1,312 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type moderation struct {
+	Db *sql.DB
+}
+
+func NewModerationInfrastructure(db *sql.DB) repository.ModerationRepository {
+	return moderation{Db: db}
+}
+
+// GetUsers retrieves a list of users along with their roles, including suspended and deleted users.
+// Only suspended users are retrieved when suspendedOnly is set.
+func (m moderation) GetUsers(suspendedOnly bool, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.email,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.is_activated,
+		u.suspended,
+		COALESCE((
+			SELECT array_agg(r.name ORDER BY r.name)
+			FROM public.user_roles ur
+			JOIN public.roles r ON r.id = ur.role_id
+			WHERE ur.user_id = u.id), '{}'),
+		u.created_at,
+		u.updated_at,
+		u._version
+	FROM public.users u
+	WHERE $1 = FALSE OR u.suspended = TRUE
+	ORDER BY u.created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := m.Db.QueryContext(ctx, query, suspendedOnly, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.Email,
+			&user.AvatarURL,
+			&user.Status,
+			&user.About,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&user.Private,
+			&user.Deleted,
+			&user.IsActivated,
+			&user.Suspended,
+			pq.Array(&user.Roles),
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// SetUserSuspended suspends or restores the user with matching id and returns the updated user.
+// repository.ErrRecordNotFound is returned if no user matches the id.
+func (m moderation) SetUserSuspended(id string, suspended bool) (models.User, error) {
+	query := `
+	UPDATE public.users
+	SET
+		suspended = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE id = $3
+	RETURNING
+		id,
+		username,
+		first_name,
+		last_name,
+		email,
+		avatar,
+		status,
+		about,
+		follower_count,
+		following_count,
+		private,
+		deleted,
+		is_activated,
+		suspended,
+		created_at,
+		updated_at,
+		_version
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	updatedUser := models.User{}
+	err := m.Db.QueryRowContext(ctx, query, suspended, time.Now().UTC(), id).
+		Scan(
+			&updatedUser.ID,
+			&updatedUser.Username,
+			&updatedUser.FirstName,
+			&updatedUser.LastName,
+			&updatedUser.Email,
+			&updatedUser.AvatarURL,
+			&updatedUser.Status,
+			&updatedUser.About,
+			&updatedUser.FollowerCount,
+			&updatedUser.FollowingCount,
+			&updatedUser.Private,
+			&updatedUser.Deleted,
+			&updatedUser.IsActivated,
+			&updatedUser.Suspended,
+			&updatedUser.CreatedAt,
+			&updatedUser.UpdatedAt,
+			&updatedUser.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.User{}, repository.ErrRecordNotFound
+
+		default:
+			return models.User{}, err
+		}
+	}
+
+	return updatedUser, nil
+}
+
+// GetMemos retrieves a list of memos, including suspended and deleted memos, most recent first.
+// Only suspended memos are retrieved when suspendedOnly is set.
+func (m moderation) GetMemos(suspendedOnly bool, page, pageSize int) ([]models.Memo, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		memo_content,
+		memo_type,
+		likes,
+		shares,
+		caption,
+		transcript,
+		deleted,
+		suspended,
+		created_at,
+		updated_at,
+		owner_id,
+		_version
+	FROM public.memos
+	WHERE $1 = FALSE OR suspended = TRUE
+	ORDER BY created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := m.Db.QueryContext(ctx, query, suspendedOnly, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	memos := make([]models.Memo, 0)
+	for rows.Next() {
+		var memo models.Memo
+		err := rows.Scan(
+			&memo.ID,
+			&memo.Content,
+			&memo.MemoType,
+			&memo.Likes,
+			&memo.Shares,
+			&memo.Caption,
+			&memo.Transcript,
+			&memo.Deleted,
+			&memo.Suspended,
+			&memo.CreatedAt,
+			&memo.UpdatedAt,
+			&memo.OwnerID,
+			&memo.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		memos = append(memos, memo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return memos, nil
+}
+
+// SetMemoSuspended suspends or restores the memo with matching id and returns the updated memo.
+// repository.ErrRecordNotFound is returned if no memo matches the id.
+func (m moderation) SetMemoSuspended(id string, suspended bool) (models.Memo, error) {
+	query := `
+	UPDATE public.memos
+	SET
+		suspended = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE id = $3
+	RETURNING
+		id,
+		memo_content,
+		memo_type,
+		likes,
+		shares,
+		caption,
+		transcript,
+		deleted,
+		suspended,
+		created_at,
+		updated_at,
+		owner_id,
+		_version
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	updatedMemo := models.Memo{}
+	err := m.Db.QueryRowContext(ctx, query, suspended, time.Now().UTC(), id).
+		Scan(
+			&updatedMemo.ID,
+			&updatedMemo.Content,
+			&updatedMemo.MemoType,
+			&updatedMemo.Likes,
+			&updatedMemo.Shares,
+			&updatedMemo.Caption,
+			&updatedMemo.Transcript,
+			&updatedMemo.Deleted,
+			&updatedMemo.Suspended,
+			&updatedMemo.CreatedAt,
+			&updatedMemo.UpdatedAt,
+			&updatedMemo.OwnerID,
+			&updatedMemo.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.Memo{}, repository.ErrRecordNotFound
+
+		default:
+			return models.Memo{}, err
+		}
+	}
+
+	return updatedMemo, nil
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type role struct {
+	Db *sql.DB
+}
+
+func NewRoleInfrastructure(db *sql.DB) repository.RoleRepository {
+	return role{Db: db}
+}
+
+// GetByUserID retrieves the roles held by a user along with the permissions each role grants.
+func (r role) GetByUserID(userID string) ([]models.Role, error) {
+	query := `
+	SELECT
+		r.id,
+		r.name,
+		COALESCE(array_agg(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}'),
+		r.created_at,
+		r.updated_at,
+		r._version
+	FROM public.user_roles ur
+	JOIN public.roles r ON r.id = ur.role_id
+	LEFT JOIN public.role_permissions rp ON rp.role_id = r.id
+	LEFT JOIN public.permissions p ON p.id = rp.permission_id
+	WHERE ur.user_id = $1
+	GROUP BY r.id
+	ORDER BY r.name
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := r.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	roles := make([]models.Role, 0)
+	for rows.Next() {
+		var role models.Role
+		err := rows.Scan(
+			&role.ID,
+			&role.Name,
+			pq.Array(&role.Permissions),
+			&role.CreatedAt,
+			&role.UpdatedAt,
+			&role.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// Assign grants the role with matching name to a user. Assigning a role the user already holds does nothing.
+// repository.ErrRecordNotFound is returned if no role matches the name.
+func (r role) Assign(userID string, roleName string) error {
+	query := `
+	INSERT INTO public.user_roles(user_id, role_id)
+	SELECT $1, id FROM public.roles WHERE name = $2
+	ON CONFLICT (user_id, role_id) DO NOTHING
+	`
+	existsQuery := `SELECT EXISTS(SELECT 1 FROM public.roles WHERE name = $1)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	if err := r.Db.QueryRowContext(ctx, existsQuery, roleName).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrRecordNotFound
+	}
+
+	_, err := r.Db.ExecContext(ctx, query, userID, roleName)
+	return err
+}
+
+// Remove takes the role with matching name away from a user.
+// repository.ErrRecordNotFound is returned if the user does not hold the role.
+func (r role) Remove(userID string, roleName string) error {
+	query := `
+	DELETE FROM public.user_roles
+	WHERE user_id = $1 AND role_id = (SELECT id FROM public.roles WHERE name = $2)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := r.Db.ExecContext(ctx, query, userID, roleName)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
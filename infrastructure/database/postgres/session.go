@@ -0,0 +1,330 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type session struct {
+	Db *sql.DB
+}
+
+func NewSessionInfrastructure(db *sql.DB) repository.SessionRepository {
+	return session{Db: db}
+}
+
+// Create starts and returns a new session for a user.
+func (s session) Create(session *models.Session) (models.Session, error) {
+	query := `
+	INSERT INTO public.sessions(user_id, device, ip_address, user_agent)
+	VALUES($1, $2, $3, $4)
+	RETURNING id, last_used_at, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newSession := *session
+	err := s.Db.QueryRowContext(
+		ctx,
+		query,
+		session.UserID,
+		session.Device,
+		session.IPAddress,
+		session.UserAgent,
+	).Scan(&newSession.ID, &newSession.LastUsedAt, &newSession.CreatedAt, &newSession.UpdatedAt)
+
+	if err != nil {
+		switch {
+		default:
+			return models.Session{}, err
+		}
+	}
+
+	return newSession, nil
+}
+
+// GetActiveByUserID retrieves the sessions of a user that have not been revoked, most recently used first.
+func (s session) GetActiveByUserID(userID string) ([]models.Session, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		device,
+		ip_address,
+		user_agent,
+		revoked,
+		last_used_at,
+		created_at,
+		updated_at,
+		_version
+	FROM public.sessions
+	WHERE user_id = $1 AND revoked = FALSE
+	ORDER BY last_used_at DESC
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := s.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	sessions := make([]models.Session, 0)
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Device,
+			&session.IPAddress,
+			&session.UserAgent,
+			&session.Revoked,
+			&session.LastUsedAt,
+			&session.CreatedAt,
+			&session.UpdatedAt,
+			&session.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Touch records that the session with matching id has just been used.
+// repository.ErrRecordNotFound is returned if the session does not belong to the user or has been revoked.
+func (s session) Touch(id string, userID string) error {
+	query := `
+	UPDATE public.sessions
+	SET last_used_at = $1
+	WHERE id = $2 AND user_id = $3 AND revoked = FALSE
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := s.Db.ExecContext(ctx, query, time.Now().UTC(), id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// RotateRefreshToken marks the refresh token with matching usedTokenID as used and stores the hash of the refresh
+// token replacing it in one transaction, so a failure leaves the used token valid for a retry.
+// repository.ErrTokenReused is returned if the token was already used or has been revoked,
+// which includes the case of two requests racing to rotate the same token.
+func (s session) RotateRefreshToken(usedTokenID string, refreshToken *models.RefreshToken) (models.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	consumeTokenQuery := `
+	UPDATE public.refresh_tokens
+	SET
+		used_at = $1,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE id = $2 AND used_at IS NULL AND revoked = FALSE;`
+	createTokenQuery := `
+	INSERT INTO public.refresh_tokens(user_id, family_id, token_hash, expires_at)
+	VALUES($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at;`
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	result, err := tx.ExecContext(ctx, consumeTokenQuery, time.Now().UTC(), usedTokenID)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+	if affected == 0 {
+		return models.RefreshToken{}, repository.ErrTokenReused
+	}
+
+	newToken := *refreshToken
+	err = tx.QueryRowContext(
+		ctx,
+		createTokenQuery,
+		refreshToken.UserID,
+		refreshToken.FamilyID,
+		refreshToken.TokenHash,
+		refreshToken.ExpiresAt,
+	).Scan(&newToken.ID, &newToken.CreatedAt, &newToken.UpdatedAt)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	return newToken, nil
+}
+
+// Revoke ends the session with matching id along with every refresh token issued to it.
+// repository.ErrRecordNotFound is returned if the session does not belong to the user or was already revoked.
+func (s session) Revoke(id string, userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	revokeSessionQuery := `
+	UPDATE public.sessions
+	SET
+		revoked = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE id = $2 AND user_id = $3 AND revoked = FALSE;`
+	revokeTokensQuery := `
+	UPDATE public.refresh_tokens
+	SET
+		revoked = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE family_id = $2 AND revoked = FALSE;`
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, revokeSessionQuery, now, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, revokeTokensQuery, now, id)
+	if err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
+
+// RevokeAll ends every session of a user along with their refresh tokens.
+// The IDs of the revoked sessions are returned.
+func (s session) RevokeAll(userID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	revokeSessionsQuery := `
+	UPDATE public.sessions
+	SET
+		revoked = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE user_id = $2 AND revoked = FALSE
+	RETURNING id;`
+	revokeTokensQuery := `
+	UPDATE public.refresh_tokens
+	SET
+		revoked = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE user_id = $2 AND revoked = FALSE;`
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	now := time.Now().UTC()
+	rows, err := tx.QueryContext(ctx, revokeSessionsQuery, now, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedIDs := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		revokedIDs = append(revokedIDs, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, revokeTokensQuery, now, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return revokedIDs, nil
+}
@@ -23,86 +23,1183 @@ func NewSocialInfrastructure(db *sql.DB) repository.SocialRepository {
 const (
 	duplicateFollowerSubjectPair = "unique_follower_subject_pair"
 	checkFollowerSubjectPair     = "check_different_ids"
+	duplicateFollowRequestPair   = "unique_follow_request_follower_id_subject_id_pair"
+	checkFollowRequestPair       = "follow_requests_self_check"
+	duplicateBlockerBlockedPair  = "unique_blocker_id_blocked_id_pair"
+	checkBlockerBlockedPair      = "blocks_self_check"
+	duplicateMuterMutedPair      = "unique_muter_id_muted_id_pair"
+	checkMuterMutedPair          = "mutes_self_check"
+	duplicateUserFriendPair      = "unique_close_friends_user_id_friend_id_pair"
+	checkUserFriendPair          = "close_friends_self_check"
 )
 
+// commentTextExpression is the text of a comment matched against muted words. Media comments are matched by caption and transcript.
+const commentTextExpression = "concat_ws(' ', CASE WHEN comments.comment_type = 'text' THEN comments.comment_content END, comments.caption, comments.transcript)"
+
+// notMutedCondition returns an SQL condition leaving out rows hidden by the mutes of the viewer bound to viewerParam:
+// rows owned by muted users, and rows by other users whose text contains an unexpired muted word, phrase or hashtag.
+// Muted words match whole words only and regardless of case; every other character in them is matched literally.
+func notMutedCondition(viewerParam, ownerColumn, textExpression string) string {
+	return `
+		AND NOT EXISTS (
+			SELECT 1 FROM public.mutes mu
+			WHERE mu.muter_id = ` + viewerParam + ` AND mu.muted_id = ` + ownerColumn + `)
+		AND NOT EXISTS (
+			SELECT 1 FROM public.muted_words mw
+			WHERE mw.user_id = ` + viewerParam + `
+				AND ` + ownerColumn + ` IS DISTINCT FROM ` + viewerParam + `
+				AND (mw.expires_at IS NULL OR mw.expires_at > now())
+				AND ` + textExpression + ` ~* (
+					'(^|[^[:alnum:]_])'
+					|| regexp_replace(mw.word, '([^[:alnum:][:space:]])', '\\\1', 'g')
+					|| '([^[:alnum:]_]|$)'))`
+}
+
 // Follow creates a new instance for a follow relationship between two users.
 func (s social) Follow(followerID, subjectID string) (models.Follow, error) {
 	query := `
-	INSERT INTO public.follow(follower_id, subject_id)
-	VALUES($1, $2)
-	RETURNING id, created_at, updated_at
+	INSERT INTO public.follow(follower_id, subject_id)
+	VALUES($1, $2)
+	RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newFollow := models.Follow{
+		FollowerID: followerID,
+		SubjectID:  subjectID,
+	}
+
+	err := s.Db.QueryRowContext(
+		ctx,
+		query,
+		followerID,
+		subjectID,
+	).Scan(&newFollow.ID, &newFollow.CreatedAt, &newFollow.UpdatedAt)
+
+	if err != nil {
+		switch {
+		case
+			strings.Contains(err.Error(), duplicateFollowerSubjectPair):
+			return models.Follow{}, repository.ErrDuplicateFollow
+		case
+			strings.Contains(err.Error(), checkFollowerSubjectPair):
+			return models.Follow{}, repository.ErrCheckFollow
+		default:
+			return models.Follow{}, err
+		}
+	}
+	err = s.updateFollowerCount(subjectID, 1)
+	if err != nil {
+		return models.Follow{}, err
+	}
+	err = s.updateFollowingCount(followerID, 1)
+	if err != nil {
+		return models.Follow{}, err
+	}
+	return newFollow, nil
+}
+
+// Unfollow deletes an instance for a follow relationship between two users.
+func (s social) Unfollow(followerID, subjectID string) (models.Follow, error) {
+	query := `
+        DELETE FROM public.follow
+        WHERE follower_id = $1 AND subject_id = $2
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := s.Db.ExecContext(ctx, query, followerID, subjectID)
+	if err != nil {
+		return models.Follow{}, err // Return the error directly
+	}
+
+	// If the deletion was successful, construct and return the Follow struct
+	newUnfollow := models.Follow{
+		FollowerID: followerID,
+		SubjectID:  subjectID,
+	}
+
+	// Update follower and following counts
+	err = s.updateFollowerCount(subjectID, -1)
+	if err != nil {
+		return models.Follow{}, err
+	}
+	err = s.updateFollowingCount(followerID, -1)
+	if err != nil {
+		return models.Follow{}, err
+	}
+
+	return newUnfollow, nil
+}
+
+// GetRelationship retrieves whether the viewer and the subject follow, block or have requested to follow one another,
+// and whether the viewer has muted the subject.
+func (s social) GetRelationship(viewerID, subjectID string) (models.Relationship, error) {
+	query := `
+	SELECT
+		EXISTS(SELECT 1 FROM public.follow WHERE follower_id = $1 AND subject_id = $2),
+		EXISTS(SELECT 1 FROM public.follow WHERE follower_id = $2 AND subject_id = $1),
+		EXISTS(SELECT 1 FROM public.blocks WHERE blocker_id = $1 AND blocked_id = $2),
+		EXISTS(SELECT 1 FROM public.blocks WHERE blocker_id = $2 AND blocked_id = $1),
+		EXISTS(SELECT 1 FROM public.mutes WHERE muter_id = $1 AND muted_id = $2),
+		EXISTS(SELECT 1 FROM public.follow_requests WHERE follower_id = $1 AND subject_id = $2),
+		EXISTS(SELECT 1 FROM public.follow_requests WHERE follower_id = $2 AND subject_id = $1)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	relationship := models.Relationship{}
+	err := s.Db.QueryRowContext(ctx, query, viewerID, subjectID).
+		Scan(
+			&relationship.Following,
+			&relationship.FollowedBy,
+			&relationship.Blocking,
+			&relationship.BlockedBy,
+			&relationship.Muting,
+			&relationship.Requested,
+			&relationship.RequestedBy,
+		)
+	if err != nil {
+		return models.Relationship{}, err
+	}
+
+	return relationship, nil
+}
+
+// GetMutualFollowers retrieves the users following both the viewer and the subject, most followed first.
+// Deleted and suspended users, and users blocking or blocked by the viewer, are left out.
+func (s social) GetMutualFollowers(viewerID, subjectID string, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.created_at,
+		u.updated_at
+	FROM public.users u
+	JOIN public.follow fv ON u.id = fv.follower_id AND fv.subject_id = $1
+	JOIN public.follow fs ON u.id = fs.follower_id AND fs.subject_id = $2
+	WHERE u.deleted = FALSE AND u.suspended = FALSE
+		AND NOT EXISTS (
+			SELECT 1 FROM public.blocks b
+			WHERE (b.blocker_id = $1 AND b.blocked_id = u.id)
+				OR (b.blocker_id = u.id AND b.blocked_id = $1))
+	ORDER BY u.follower_count DESC, u.id
+	LIMIT $3 OFFSET $4
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := s.Db.QueryContext(ctx, query, viewerID, subjectID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.AvatarURL,
+			&user.Status,
+			&user.About,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&user.Private,
+			&user.Deleted,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// suggestionPoolSize bounds how many of the most popular users among recently active users are considered
+// as follow suggestions, besides friends of friends and followers.
+const suggestionPoolSize = 500
+
+// GetSuggestions retrieves users for a user to follow: users followed by the users they follow, users following them
+// whom they do not follow back, and users popular among users active since activeSince.
+// Candidates are ranked by mutual follows, then by whether they follow the user, then by popularity.
+// Users already followed or requested, blocked either way, muted, deleted or suspended are left out.
+func (s social) GetSuggestions(userID string, activeSince time.Time, limit int) ([]models.Suggestion, error) {
+	query := `
+	WITH following AS (
+		SELECT subject_id FROM public.follow WHERE follower_id = $1
+	), mutuals AS (
+		SELECT f.subject_id AS id, count(*) AS mutual_count
+		FROM public.follow f
+		JOIN following fo ON f.follower_id = fo.subject_id
+		GROUP BY f.subject_id
+	), followers AS (
+		SELECT follower_id AS id FROM public.follow WHERE subject_id = $1
+	), popular AS (
+		SELECT f.subject_id AS id, count(*) AS active_follower_count
+		FROM public.follow f
+		WHERE f.follower_id IN (
+			SELECT user_id FROM public.sessions WHERE revoked = FALSE AND last_used_at >= $2)
+		GROUP BY f.subject_id
+		ORDER BY active_follower_count DESC
+		LIMIT $4
+	), candidates AS (
+		SELECT id FROM mutuals
+		UNION
+		SELECT id FROM followers
+		UNION
+		SELECT id FROM popular
+	)
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.created_at,
+		u.updated_at,
+		COALESCE(m.mutual_count, 0) AS mutual_rank,
+		fl.id IS NOT NULL AS follower_rank,
+		COALESCE(p.active_follower_count, 0) AS popularity_rank
+	FROM candidates c
+	JOIN public.users u ON u.id = c.id
+	LEFT JOIN mutuals m ON m.id = c.id
+	LEFT JOIN followers fl ON fl.id = c.id
+	LEFT JOIN popular p ON p.id = c.id
+	WHERE u.id <> $1 AND u.deleted = FALSE AND u.suspended = FALSE
+		AND NOT EXISTS (SELECT 1 FROM following fo WHERE fo.subject_id = u.id)
+		AND NOT EXISTS (
+			SELECT 1 FROM public.follow_requests fr
+			WHERE fr.follower_id = $1 AND fr.subject_id = u.id)
+		AND NOT EXISTS (
+			SELECT 1 FROM public.blocks b
+			WHERE (b.blocker_id = $1 AND b.blocked_id = u.id)
+				OR (b.blocker_id = u.id AND b.blocked_id = $1))
+		AND NOT EXISTS (
+			SELECT 1 FROM public.mutes mu
+			WHERE mu.muter_id = $1 AND mu.muted_id = u.id)
+	ORDER BY mutual_rank DESC, follower_rank DESC, popularity_rank DESC, u.follower_count DESC, u.id
+	LIMIT $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := s.Db.QueryContext(ctx, query, userID, activeSince, limit, suggestionPoolSize)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	suggestions := make([]models.Suggestion, 0)
+	for rows.Next() {
+		var suggestion models.Suggestion
+		err := rows.Scan(
+			&suggestion.User.ID,
+			&suggestion.User.Username,
+			&suggestion.User.FirstName,
+			&suggestion.User.LastName,
+			&suggestion.User.AvatarURL,
+			&suggestion.User.Status,
+			&suggestion.User.About,
+			&suggestion.User.FollowerCount,
+			&suggestion.User.FollowingCount,
+			&suggestion.User.Private,
+			&suggestion.User.Deleted,
+			&suggestion.User.CreatedAt,
+			&suggestion.User.UpdatedAt,
+			&suggestion.MutualCount,
+			&suggestion.FollowsViewer,
+			&suggestion.ActiveFollowerCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// RequestFollow asks a private user for approval to follow them.
+// repository.ErrDuplicateFollowRequest is returned if the request has already been sent.
+func (s social) RequestFollow(followerID, subjectID string) (models.FollowRequest, error) {
+	query := `
+	INSERT INTO public.follow_requests(follower_id, subject_id)
+	VALUES($1, $2)
+	RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newRequest := models.FollowRequest{
+		FollowerID: followerID,
+		SubjectID:  subjectID,
+	}
+	err := s.Db.QueryRowContext(ctx, query, followerID, subjectID).
+		Scan(&newRequest.ID, &newRequest.CreatedAt, &newRequest.UpdatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateFollowRequestPair):
+			return models.FollowRequest{}, repository.ErrDuplicateFollowRequest
+		case strings.Contains(err.Error(), checkFollowRequestPair):
+			return models.FollowRequest{}, repository.ErrCheckFollow
+		default:
+			return models.FollowRequest{}, err
+		}
+	}
+
+	return newRequest, nil
+}
+
+// GetFollowRequests retrieves the users waiting for a user to approve their follow requests, oldest request first.
+func (s social) GetFollowRequests(subjectID string, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.created_at,
+		u.updated_at
+	FROM public.users u
+	JOIN public.follow_requests fr ON u.id = fr.follower_id
+	WHERE fr.subject_id = $1
+	ORDER BY fr.created_at
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := s.Db.QueryContext(ctx, query, subjectID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.AvatarURL,
+			&user.Status,
+			&user.About,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&user.Private,
+			&user.Deleted,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// ApproveFollowRequest turns a follow request into a follow, updating the follower and following counts.
+// repository.ErrRecordNotFound is returned if there is no such follow request.
+func (s social) ApproveFollowRequest(subjectID, followerID string) (models.Follow, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	deleteRequestQuery := `
+	DELETE FROM public.follow_requests
+	WHERE follower_id = $1 AND subject_id = $2;`
+	followQuery := `
+	INSERT INTO public.follow(follower_id, subject_id)
+	VALUES($1, $2)
+	ON CONFLICT ON CONSTRAINT ` + duplicateFollowerSubjectPair + ` DO NOTHING
+	RETURNING id, created_at, updated_at;`
+	countsQuery := `
+	UPDATE public.users
+	SET
+		follower_count = follower_count + CASE WHEN id = $2 THEN 1 ELSE 0 END,
+		following_count = following_count + CASE WHEN id = $1 THEN 1 ELSE 0 END
+	WHERE id IN ($1, $2);`
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Follow{}, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	result, err := tx.ExecContext(ctx, deleteRequestQuery, followerID, subjectID)
+	if err != nil {
+		return models.Follow{}, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return models.Follow{}, err
+	}
+	if affected == 0 {
+		return models.Follow{}, repository.ErrRecordNotFound
+	}
+
+	newFollow := models.Follow{
+		FollowerID: followerID,
+		SubjectID:  subjectID,
+	}
+	err = tx.QueryRowContext(ctx, followQuery, followerID, subjectID).
+		Scan(&newFollow.ID, &newFollow.CreatedAt, &newFollow.UpdatedAt)
+	if err != nil {
+		switch {
+		// the follower was already following, so only the request is removed
+		case errors.Is(err, sql.ErrNoRows):
+			if err := tx.Commit(); err != nil {
+				return models.Follow{}, err
+			}
+			return newFollow, nil
+		default:
+			return models.Follow{}, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, countsQuery, followerID, subjectID); err != nil {
+		return models.Follow{}, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return models.Follow{}, err
+	}
+
+	return newFollow, nil
+}
+
+// ApproveAllFollowRequests turns every follow request to a user into a follow, updating the follower and
+// following counts. It is used when a private user becomes public.
+func (s social) ApproveAllFollowRequests(subjectID string) error {
+	query := `
+	WITH requests AS (
+		DELETE FROM public.follow_requests
+		WHERE subject_id = $1
+		RETURNING follower_id
+	), followed AS (
+		INSERT INTO public.follow(follower_id, subject_id)
+		SELECT follower_id, $1 FROM requests
+		ON CONFLICT ON CONSTRAINT ` + duplicateFollowerSubjectPair + ` DO NOTHING
+		RETURNING follower_id
+	)
+	UPDATE public.users u
+	SET
+		follower_count = follower_count + CASE WHEN u.id = $1 THEN (SELECT count(*) FROM followed) ELSE 0 END,
+		following_count = following_count + CASE WHEN u.id = $1 THEN 0 ELSE 1 END
+	WHERE u.id = $1 OR u.id IN (SELECT follower_id FROM followed)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := s.Db.ExecContext(ctx, query, subjectID)
+	return err
+}
+
+// DeleteFollowRequest rejects or withdraws a follow request.
+// repository.ErrRecordNotFound is returned if there is no such follow request.
+func (s social) DeleteFollowRequest(followerID, subjectID string) error {
+	query := `
+	DELETE FROM public.follow_requests
+	WHERE follower_id = $1 AND subject_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := s.Db.ExecContext(ctx, query, followerID, subjectID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Block stops two users from seeing or interacting with each other and removes any follow, follow request or
+// close friend between them, in either direction, correcting the follower and following counts of both users.
+// repository.ErrDuplicateBlock is returned if the user is already blocked.
+func (s social) Block(blockerID, blockedID string) (models.Block, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	blockQuery := `
+	INSERT INTO public.blocks(blocker_id, blocked_id)
+	VALUES($1, $2)
+	RETURNING id, created_at, updated_at;`
+	unfollowQuery := `
+	WITH removed AS (
+		DELETE FROM public.follow
+		WHERE (follower_id = $1 AND subject_id = $2) OR (follower_id = $2 AND subject_id = $1)
+		RETURNING follower_id, subject_id
+	)
+	UPDATE public.users u
+	SET
+		following_count = following_count - (SELECT count(*) FROM removed WHERE follower_id = u.id),
+		follower_count = follower_count - (SELECT count(*) FROM removed WHERE subject_id = u.id)
+	WHERE u.id IN ($1, $2);`
+	deleteRequestsQuery := `
+	DELETE FROM public.follow_requests
+	WHERE (follower_id = $1 AND subject_id = $2) OR (follower_id = $2 AND subject_id = $1);`
+	deleteCloseFriendsQuery := `
+	DELETE FROM public.close_friends
+	WHERE (user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1);`
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Block{}, err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	newBlock := models.Block{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+	}
+	err = tx.QueryRowContext(ctx, blockQuery, blockerID, blockedID).
+		Scan(&newBlock.ID, &newBlock.CreatedAt, &newBlock.UpdatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateBlockerBlockedPair):
+			return models.Block{}, repository.ErrDuplicateBlock
+		case strings.Contains(err.Error(), checkBlockerBlockedPair):
+			return models.Block{}, repository.ErrCheckBlock
+		default:
+			return models.Block{}, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, unfollowQuery, blockerID, blockedID); err != nil {
+		return models.Block{}, err
+	}
+	if _, err := tx.ExecContext(ctx, deleteRequestsQuery, blockerID, blockedID); err != nil {
+		return models.Block{}, err
+	}
+	if _, err := tx.ExecContext(ctx, deleteCloseFriendsQuery, blockerID, blockedID); err != nil {
+		return models.Block{}, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(); err != nil {
+		return models.Block{}, err
+	}
+
+	return newBlock, nil
+}
+
+// Unblock lifts a block. Follows removed by the block are not restored.
+// repository.ErrRecordNotFound is returned if the user is not blocked.
+func (s social) Unblock(blockerID, blockedID string) error {
+	query := `
+	DELETE FROM public.blocks
+	WHERE blocker_id = $1 AND blocked_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := s.Db.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetBlockedUsers retrieves the users blocked by a user, most recently blocked first.
+func (s social) GetBlockedUsers(blockerID string, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.created_at,
+		u.updated_at
+	FROM public.users u
+	JOIN public.blocks b ON u.id = b.blocked_id
+	WHERE b.blocker_id = $1
+	ORDER BY b.created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := s.Db.QueryContext(ctx, query, blockerID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.AvatarURL,
+			&user.Status,
+			&user.About,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&user.Private,
+			&user.Deleted,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// IsBlocked reports whether either of two users has blocked the other.
+func (s social) IsBlocked(userID, otherUserID string) (bool, error) {
+	query := `
+	SELECT EXISTS(
+		SELECT 1
+		FROM public.blocks
+		WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+	)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	var blocked bool
+	if err := s.Db.QueryRowContext(ctx, query, userID, otherUserID).Scan(&blocked); err != nil {
+		return false, err
+	}
+
+	return blocked, nil
+}
+
+// Mute hides the memos and comments of a user from the muter's feeds and comment listings.
+// repository.ErrDuplicateMute is returned if the user is already muted.
+func (s social) Mute(muterID, mutedID string) (models.Mute, error) {
+	query := `
+	INSERT INTO public.mutes(muter_id, muted_id)
+	VALUES($1, $2)
+	RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newMute := models.Mute{
+		MuterID: muterID,
+		MutedID: mutedID,
+	}
+	err := s.Db.QueryRowContext(ctx, query, muterID, mutedID).
+		Scan(&newMute.ID, &newMute.CreatedAt, &newMute.UpdatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateMuterMutedPair):
+			return models.Mute{}, repository.ErrDuplicateMute
+		case strings.Contains(err.Error(), checkMuterMutedPair):
+			return models.Mute{}, repository.ErrCheckMute
+		default:
+			return models.Mute{}, err
+		}
+	}
+
+	return newMute, nil
+}
+
+// Unmute lifts a mute. repository.ErrRecordNotFound is returned if the user is not muted.
+func (s social) Unmute(muterID, mutedID string) error {
+	query := `
+	DELETE FROM public.mutes
+	WHERE muter_id = $1 AND muted_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := s.Db.ExecContext(ctx, query, muterID, mutedID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetMutedUsers retrieves the users muted by a user, most recently muted first.
+func (s social) GetMutedUsers(muterID string, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.created_at,
+		u.updated_at
+	FROM public.users u
+	JOIN public.mutes mu ON u.id = mu.muted_id
+	WHERE mu.muter_id = $1
+	ORDER BY mu.created_at DESC
+	LIMIT $2 OFFSET $3
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
-	newFollow := models.Follow{
-		FollowerID: followerID,
-		SubjectID:  subjectID,
+	rows, err := s.Db.QueryContext(ctx, query, muterID, pageSize, offset)
+	if err != nil {
+		return nil, err
 	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
 
-	err := s.Db.QueryRowContext(
-		ctx,
-		query,
-		followerID,
-		subjectID,
-	).Scan(&newFollow.ID, &newFollow.CreatedAt, &newFollow.UpdatedAt)
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.AvatarURL,
+			&user.Status,
+			&user.About,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&user.Private,
+			&user.Deleted,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// MuteWord mutes a word, phrase or hashtag for a user. A muted word that has expired is muted again.
+// repository.ErrDuplicateMutedWord is returned if the word is already muted, ignoring case.
+func (s social) MuteWord(mutedWord *models.MutedWord) (models.MutedWord, error) {
+	query := `
+	INSERT INTO public.muted_words(user_id, word, expires_at)
+	VALUES($1, $2, $3)
+	ON CONFLICT (user_id, lower(word)) DO UPDATE
+	SET
+		word = EXCLUDED.word,
+		expires_at = EXCLUDED.expires_at,
+		created_at = now(),
+		updated_at = now(),
+		_version = public.muted_words._version + 1
+	WHERE public.muted_words.expires_at <= now()
+	RETURNING id, created_at, updated_at, _version
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
 
+	newMutedWord := *mutedWord
+	err := s.Db.QueryRowContext(ctx, query, mutedWord.UserID, mutedWord.Word, mutedWord.ExpiresAt).
+		Scan(&newMutedWord.ID, &newMutedWord.CreatedAt, &newMutedWord.UpdatedAt, &newMutedWord.Version)
 	if err != nil {
 		switch {
-		case
-			strings.Contains(err.Error(), duplicateFollowerSubjectPair):
-			return models.Follow{}, repository.ErrDuplicateFollow
-		case
-			strings.Contains(err.Error(), checkFollowerSubjectPair):
-			return models.Follow{}, repository.ErrCheckFollow
+		// no row is returned when the word is already muted and has not expired
+		case errors.Is(err, sql.ErrNoRows):
+			return models.MutedWord{}, repository.ErrDuplicateMutedWord
 		default:
-			return models.Follow{}, err
+			return models.MutedWord{}, err
 		}
 	}
-	err = s.updateFollowerCount(subjectID, 1)
+
+	return newMutedWord, nil
+}
+
+// UnmuteWord removes a muted word of a user.
+// repository.ErrRecordNotFound is returned if the user has no muted word with a matching id.
+func (s social) UnmuteWord(userID, mutedWordID string) error {
+	query := `
+	DELETE FROM public.muted_words
+	WHERE id = $1 AND user_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := s.Db.ExecContext(ctx, query, mutedWordID, userID)
 	if err != nil {
-		return models.Follow{}, err
+		return err
 	}
-	err = s.updateFollowingCount(followerID, 1)
+
+	affected, err := result.RowsAffected()
 	if err != nil {
-		return models.Follow{}, err
+		return err
 	}
-	return newFollow, nil
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
 }
 
-// Unfollow deletes an instance for a follow relationship between two users.
-func (s social) Unfollow(followerID, subjectID string) (models.Follow, error) {
+// GetMutedWords retrieves the unexpired muted words of a user, most recently muted first.
+func (s social) GetMutedWords(userID string) ([]models.MutedWord, error) {
 	query := `
-        DELETE FROM public.follow
-        WHERE follower_id = $1 AND subject_id = $2
-    `
+	SELECT id, user_id, word, expires_at, created_at, updated_at, _version
+	FROM public.muted_words
+	WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > now())
+	ORDER BY created_at DESC
+	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
-	_, err := s.Db.ExecContext(ctx, query, followerID, subjectID)
+	rows, err := s.Db.QueryContext(ctx, query, userID)
 	if err != nil {
-		return models.Follow{}, err // Return the error directly
+		return nil, err
 	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
 
-	// If the deletion was successful, construct and return the Follow struct
-	newUnfollow := models.Follow{
-		FollowerID: followerID,
-		SubjectID:  subjectID,
+	mutedWords := make([]models.MutedWord, 0)
+	for rows.Next() {
+		var mutedWord models.MutedWord
+		err := rows.Scan(
+			&mutedWord.ID,
+			&mutedWord.UserID,
+			&mutedWord.Word,
+			&mutedWord.ExpiresAt,
+			&mutedWord.CreatedAt,
+			&mutedWord.UpdatedAt,
+			&mutedWord.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		mutedWords = append(mutedWords, mutedWord)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	// Update follower and following counts
-	err = s.updateFollowerCount(subjectID, -1)
+	return mutedWords, nil
+}
+
+// AddCloseFriend adds a user to the close friends of another user.
+// repository.ErrDuplicateCloseFriend is returned if the user is already a close friend.
+func (s social) AddCloseFriend(userID, friendID string) (models.CloseFriend, error) {
+	query := `
+	INSERT INTO public.close_friends(user_id, friend_id)
+	VALUES($1, $2)
+	RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newCloseFriend := models.CloseFriend{
+		UserID:   userID,
+		FriendID: friendID,
+	}
+	err := s.Db.QueryRowContext(ctx, query, userID, friendID).
+		Scan(&newCloseFriend.ID, &newCloseFriend.CreatedAt, &newCloseFriend.UpdatedAt)
 	if err != nil {
-		return models.Follow{}, err
+		switch {
+		case strings.Contains(err.Error(), duplicateUserFriendPair):
+			return models.CloseFriend{}, repository.ErrDuplicateCloseFriend
+		case strings.Contains(err.Error(), checkUserFriendPair):
+			return models.CloseFriend{}, repository.ErrCheckCloseFriend
+		default:
+			return models.CloseFriend{}, err
+		}
 	}
-	err = s.updateFollowingCount(followerID, -1)
+
+	return newCloseFriend, nil
+}
+
+// RemoveCloseFriend removes a user from the close friends of another user.
+// repository.ErrRecordNotFound is returned if the user is not a close friend.
+func (s social) RemoveCloseFriend(userID, friendID string) error {
+	query := `
+	DELETE FROM public.close_friends
+	WHERE user_id = $1 AND friend_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := s.Db.ExecContext(ctx, query, userID, friendID)
 	if err != nil {
-		return models.Follow{}, err
+		return err
 	}
 
-	return newUnfollow, nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetCloseFriends retrieves the close friends of a user, most recently added first.
+func (s social) GetCloseFriends(userID string, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		u.avatar,
+		u.status,
+		u.about,
+		u.follower_count,
+		u.following_count,
+		u.private,
+		u.deleted,
+		u.created_at,
+		u.updated_at
+	FROM public.users u
+	JOIN public.close_friends cf ON u.id = cf.friend_id
+	WHERE cf.user_id = $1
+	ORDER BY cf.created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := s.Db.QueryContext(ctx, query, userID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.AvatarURL,
+			&user.Status,
+			&user.About,
+			&user.FollowerCount,
+			&user.FollowingCount,
+			&user.Private,
+			&user.Deleted,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// IsCloseFriend reports whether friendID is one of the close friends of userID.
+func (s social) IsCloseFriend(userID, friendID string) (bool, error) {
+	query := `
+	SELECT EXISTS(
+		SELECT 1
+		FROM public.close_friends
+		WHERE user_id = $1 AND friend_id = $2
+	)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	var closeFriend bool
+	if err := s.Db.QueryRowContext(ctx, query, userID, friendID).Scan(&closeFriend); err != nil {
+		return false, err
+	}
+
+	return closeFriend, nil
 }
 
 // UpdateFollowerCount updates the FollowerCount for a given user by a specified increment.
@@ -280,7 +1377,7 @@ func (s social) GetComment(id string) (models.Comment, error) {
 		deleted,
 		created_at,
 		updated_at,
-		owner_id,
+		COALESCE(owner_id::text, ''),
 		_version
 	FROM public.comments
 	WHERE id = $1
@@ -324,7 +1421,7 @@ func (s social) GetComment(id string) (models.Comment, error) {
 	return foundComment, nil
 }
 
-func (s social) GetCommentsByMemoID(memoID string, page, pageSize int) ([]models.Comment, error) {
+func (s social) GetCommentsByMemoID(memoID string, viewerID string, page, pageSize int) ([]models.Comment, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -344,16 +1441,21 @@ func (s social) GetCommentsByMemoID(memoID string, page, pageSize int) ([]models
        deleted,
        created_at,
        updated_at,
-       owner_id
+       COALESCE(owner_id::text, '')
 	FROM public.comments
 	WHERE memo_id = $1 AND parent_id IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM public.blocks b
+			WHERE (b.blocker_id = $4 AND b.blocked_id = comments.owner_id)
+				OR (b.blocker_id = comments.owner_id AND b.blocked_id = $4))` +
+		notMutedCondition("$4", "comments.owner_id", commentTextExpression) + `
 	ORDER BY created_at
 	OFFSET $2 LIMIT $3`
 
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
-	rows, err := s.Db.QueryContext(ctx, query, memoID, offset, pageSize)
+	rows, err := s.Db.QueryContext(ctx, query, memoID, offset, pageSize, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -400,7 +1502,7 @@ func (s social) GetCommentsByMemoID(memoID string, page, pageSize int) ([]models
 	return comments, nil
 }
 
-func (s social) GetRepliesByParentID(parentID string, page, pageSize int) ([]models.Comment, error) {
+func (s social) GetRepliesByParentID(parentID string, viewerID string, page, pageSize int) ([]models.Comment, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -420,16 +1522,21 @@ func (s social) GetRepliesByParentID(parentID string, page, pageSize int) ([]mod
        deleted,
        created_at,
        updated_at,
-       owner_id
+       COALESCE(owner_id::text, '')
 	FROM public.comments
 	WHERE parent_id = $1
+		AND NOT EXISTS (
+			SELECT 1 FROM public.blocks b
+			WHERE (b.blocker_id = $4 AND b.blocked_id = comments.owner_id)
+				OR (b.blocker_id = comments.owner_id AND b.blocked_id = $4))` +
+		notMutedCondition("$4", "comments.owner_id", commentTextExpression) + `
 	ORDER BY created_at
 	OFFSET $2 LIMIT $3`
 
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
-	rows, err := s.Db.QueryContext(ctx, query, parentID, offset, pageSize)
+	rows, err := s.Db.QueryContext(ctx, query, parentID, offset, pageSize, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -486,3 +1593,75 @@ func (s social) GetRepliesByParentID(parentID string, page, pageSize int) ([]mod
 //		}
 //	}
 //}
+
+// GetCommentsByOwnerID retrieves the comments and replies left by a user, oldest first.
+func (s social) GetCommentsByOwnerID(ownerID string, page, pageSize int) ([]models.Comment, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		memo_id,
+		parent_id,
+		comment_content,
+		comment_type,
+		likes,
+		caption,
+		transcript,
+		deleted,
+		created_at,
+		updated_at,
+		owner_id
+	FROM public.comments
+	WHERE owner_id = $1
+	ORDER BY created_at
+	OFFSET $2 LIMIT $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := s.Db.QueryContext(ctx, query, ownerID, offset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	comments := make([]models.Comment, 0)
+	for rows.Next() {
+		var comment models.Comment
+		err := rows.Scan(
+			&comment.ID,
+			&comment.MemoID,
+			&comment.ParentID,
+			&comment.Content,
+			&comment.CommentType,
+			&comment.Likes,
+			&comment.Caption,
+			&comment.Transcript,
+			&comment.Deleted,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+			&comment.OwnerID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
@@ -0,0 +1,494 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type token struct {
+	Db *sql.DB
+}
+
+func NewTokenInfrastructure(db *sql.DB) repository.TokenRepository {
+	return token{Db: db}
+}
+
+// CreateRefreshToken stores the hash of a newly issued refresh token.
+func (t token) CreateRefreshToken(refreshToken *models.RefreshToken) (models.RefreshToken, error) {
+	query := `
+	INSERT INTO public.refresh_tokens(user_id, family_id, token_hash, expires_at)
+	VALUES($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newToken := *refreshToken
+	err := t.Db.QueryRowContext(
+		ctx,
+		query,
+		refreshToken.UserID,
+		refreshToken.FamilyID,
+		refreshToken.TokenHash,
+		refreshToken.ExpiresAt,
+	).Scan(&newToken.ID, &newToken.CreatedAt, &newToken.UpdatedAt)
+
+	if err != nil {
+		switch {
+		default:
+			return models.RefreshToken{}, err
+		}
+	}
+
+	return newToken, nil
+}
+
+// GetRefreshTokenByHash retrieves a stored refresh token via its hash.
+// repository.ErrRecordNotFound is returned if no refresh token matches the query.
+func (t token) GetRefreshTokenByHash(tokenHash string) (models.RefreshToken, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		family_id,
+		token_hash,
+		expires_at,
+		used_at,
+		revoked,
+		created_at,
+		updated_at,
+		_version
+	FROM public.refresh_tokens
+	WHERE token_hash = $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	foundToken := models.RefreshToken{}
+	err := t.Db.QueryRowContext(ctx, query, tokenHash).
+		Scan(
+			&foundToken.ID,
+			&foundToken.UserID,
+			&foundToken.FamilyID,
+			&foundToken.TokenHash,
+			&foundToken.ExpiresAt,
+			&foundToken.UsedAt,
+			&foundToken.Revoked,
+			&foundToken.CreatedAt,
+			&foundToken.UpdatedAt,
+			&foundToken.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.RefreshToken{}, repository.ErrRecordNotFound
+
+		default:
+			return models.RefreshToken{}, err
+		}
+	}
+
+	return foundToken, nil
+}
+
+// RevokeRefreshTokenFamily revokes every refresh token descended from the same login.
+func (t token) RevokeRefreshTokenFamily(familyID string) error {
+	query := `
+	UPDATE public.refresh_tokens
+	SET
+		revoked = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE family_id = $2 AND revoked = FALSE
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := t.Db.ExecContext(ctx, query, time.Now().UTC(), familyID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token issued to the user with matching id.
+func (t token) RevokeUserRefreshTokens(userID string) error {
+	query := `
+	UPDATE public.refresh_tokens
+	SET
+		revoked = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE user_id = $2 AND revoked = FALSE
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := t.Db.ExecContext(ctx, query, time.Now().UTC(), userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreatePasswordResetToken stores the hash of a newly issued password reset token.
+func (t token) CreatePasswordResetToken(resetToken *models.PasswordResetToken) (models.PasswordResetToken, error) {
+	query := `
+	INSERT INTO public.password_reset_tokens(user_id, token_hash, expires_at)
+	VALUES($1, $2, $3)
+	RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newToken := *resetToken
+	err := t.Db.QueryRowContext(
+		ctx,
+		query,
+		resetToken.UserID,
+		resetToken.TokenHash,
+		resetToken.ExpiresAt,
+	).Scan(&newToken.ID, &newToken.CreatedAt, &newToken.UpdatedAt)
+
+	if err != nil {
+		switch {
+		default:
+			return models.PasswordResetToken{}, err
+		}
+	}
+
+	return newToken, nil
+}
+
+// GetPasswordResetTokenByHash retrieves a stored password reset token via its hash.
+// repository.ErrRecordNotFound is returned if no password reset token matches the query.
+func (t token) GetPasswordResetTokenByHash(tokenHash string) (models.PasswordResetToken, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		token_hash,
+		expires_at,
+		used_at,
+		created_at,
+		updated_at,
+		_version
+	FROM public.password_reset_tokens
+	WHERE token_hash = $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	foundToken := models.PasswordResetToken{}
+	err := t.Db.QueryRowContext(ctx, query, tokenHash).
+		Scan(
+			&foundToken.ID,
+			&foundToken.UserID,
+			&foundToken.TokenHash,
+			&foundToken.ExpiresAt,
+			&foundToken.UsedAt,
+			&foundToken.CreatedAt,
+			&foundToken.UpdatedAt,
+			&foundToken.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.PasswordResetToken{}, repository.ErrRecordNotFound
+
+		default:
+			return models.PasswordResetToken{}, err
+		}
+	}
+
+	return foundToken, nil
+}
+
+// ResetPassword uses up the password reset token with matching id and sets the password hash of its user,
+// all in one transaction so that the token stays usable if the password cannot be saved.
+// Every other unused reset token of the user is used up along with it.
+// repository.ErrTokenReused is returned if the token was already used.
+func (t token) ResetPassword(id string, userID string, passwordHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	consumeTokenQuery := `
+	UPDATE public.password_reset_tokens
+	SET
+		used_at = $1,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE id = $2 AND user_id = $3 AND used_at IS NULL;`
+	updatePasswordQuery := `
+	UPDATE public.users
+	SET
+		password_hash = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE id = $3;`
+	consumeOtherTokensQuery := `
+	UPDATE public.password_reset_tokens
+	SET
+		used_at = $1,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE user_id = $2 AND used_at IS NULL;`
+
+	tx, err := t.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	// this fails if a concurrent request got to the token first
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, consumeTokenQuery, now, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrTokenReused
+	}
+
+	result, err = tx.ExecContext(ctx, updatePasswordQuery, passwordHash, now, userID)
+	if err != nil {
+		return err
+	}
+	affected, err = result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, consumeOtherTokensQuery, now, userID)
+	if err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
+
+// CreatePersonalAccessToken stores the hash of a newly created personal access token.
+func (t token) CreatePersonalAccessToken(accessToken *models.PersonalAccessToken) (models.PersonalAccessToken, error) {
+	query := `
+	INSERT INTO public.personal_access_tokens(user_id, name, token_hash, scopes, expires_at)
+	VALUES($1, $2, $3, $4, $5)
+	RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	newToken := *accessToken
+	err := t.Db.QueryRowContext(
+		ctx,
+		query,
+		accessToken.UserID,
+		accessToken.Name,
+		accessToken.TokenHash,
+		pq.Array(accessToken.Scopes),
+		accessToken.ExpiresAt,
+	).Scan(&newToken.ID, &newToken.CreatedAt, &newToken.UpdatedAt)
+
+	if err != nil {
+		switch {
+		default:
+			return models.PersonalAccessToken{}, err
+		}
+	}
+
+	return newToken, nil
+}
+
+// GetPersonalAccessTokenByHash retrieves a stored personal access token via its hash.
+// repository.ErrRecordNotFound is returned if no personal access token matches the query.
+func (t token) GetPersonalAccessTokenByHash(tokenHash string) (models.PersonalAccessToken, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		name,
+		token_hash,
+		scopes,
+		expires_at,
+		last_used_at,
+		revoked,
+		created_at,
+		updated_at,
+		_version
+	FROM public.personal_access_tokens
+	WHERE token_hash = $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	foundToken := models.PersonalAccessToken{}
+	err := t.Db.QueryRowContext(ctx, query, tokenHash).
+		Scan(
+			&foundToken.ID,
+			&foundToken.UserID,
+			&foundToken.Name,
+			&foundToken.TokenHash,
+			pq.Array(&foundToken.Scopes),
+			&foundToken.ExpiresAt,
+			&foundToken.LastUsedAt,
+			&foundToken.Revoked,
+			&foundToken.CreatedAt,
+			&foundToken.UpdatedAt,
+			&foundToken.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.PersonalAccessToken{}, repository.ErrRecordNotFound
+
+		default:
+			return models.PersonalAccessToken{}, err
+		}
+	}
+
+	return foundToken, nil
+}
+
+// GetPersonalAccessTokensByUserID retrieves the personal access tokens of a user that have not been revoked,
+// most recently created first.
+func (t token) GetPersonalAccessTokensByUserID(userID string) ([]models.PersonalAccessToken, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		name,
+		token_hash,
+		scopes,
+		expires_at,
+		last_used_at,
+		revoked,
+		created_at,
+		updated_at,
+		_version
+	FROM public.personal_access_tokens
+	WHERE user_id = $1 AND revoked = FALSE
+	ORDER BY created_at DESC
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := t.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	accessTokens := make([]models.PersonalAccessToken, 0)
+	for rows.Next() {
+		var accessToken models.PersonalAccessToken
+		err := rows.Scan(
+			&accessToken.ID,
+			&accessToken.UserID,
+			&accessToken.Name,
+			&accessToken.TokenHash,
+			pq.Array(&accessToken.Scopes),
+			&accessToken.ExpiresAt,
+			&accessToken.LastUsedAt,
+			&accessToken.Revoked,
+			&accessToken.CreatedAt,
+			&accessToken.UpdatedAt,
+			&accessToken.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		accessTokens = append(accessTokens, accessToken)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accessTokens, nil
+}
+
+// TouchPersonalAccessToken records that the personal access token with matching id has just been used.
+func (t token) TouchPersonalAccessToken(id string) error {
+	query := `
+	UPDATE public.personal_access_tokens
+	SET last_used_at = $1
+	WHERE id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := t.Db.ExecContext(ctx, query, time.Now().UTC(), id)
+	return err
+}
+
+// RevokePersonalAccessToken revokes the personal access token with matching id.
+// repository.ErrRecordNotFound is returned if the token does not belong to the user or was already revoked.
+func (t token) RevokePersonalAccessToken(id string, userID string) error {
+	query := `
+	UPDATE public.personal_access_tokens
+	SET
+		revoked = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE id = $2 AND user_id = $3 AND revoked = FALSE
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := t.Db.ExecContext(ctx, query, time.Now().UTC(), id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
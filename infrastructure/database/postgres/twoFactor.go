@@ -0,0 +1,405 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+	"github.com/akinolaemmanuel49/memo-api/internal/helpers"
+)
+
+type twoFactor struct {
+	Db *sql.DB
+}
+
+func NewTwoFactorInfrastructure(db *sql.DB) repository.TwoFactorRepository {
+	return twoFactor{Db: db}
+}
+
+// Get retrieves the TOTP enrollment of a user.
+// repository.ErrRecordNotFound is returned if the user has never enrolled.
+func (t twoFactor) Get(userID string) (models.TwoFactor, error) {
+	query := `
+	SELECT
+		user_id,
+		totp_secret,
+		enabled,
+		last_used_step,
+		created_at,
+		updated_at,
+		_version
+	FROM public.two_factor
+	WHERE user_id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	found := models.TwoFactor{}
+	err := t.Db.QueryRowContext(ctx, query, userID).
+		Scan(
+			&found.UserID,
+			&found.TOTPSecret,
+			&found.Enabled,
+			&found.LastUsedStep,
+			&found.CreatedAt,
+			&found.UpdatedAt,
+			&found.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.TwoFactor{}, repository.ErrRecordNotFound
+
+		default:
+			return models.TwoFactor{}, err
+		}
+	}
+
+	return found, nil
+}
+
+// SetPendingSecret starts a TOTP enrollment for a user, replacing any enrollment that was never confirmed.
+// repository.ErrTwoFactorEnabled is returned if the user already has two-factor authentication enabled.
+func (t twoFactor) SetPendingSecret(userID string, secret string) (models.TwoFactor, error) {
+	query := `
+	INSERT INTO public.two_factor(user_id, totp_secret)
+	VALUES($1, $2)
+	ON CONFLICT (user_id) DO UPDATE
+	SET
+		totp_secret = EXCLUDED.totp_secret,
+		last_used_step = 0,
+		updated_at = now(),
+		_version = public.two_factor._version + 1
+	WHERE public.two_factor.enabled = FALSE
+	RETURNING created_at, updated_at, _version
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	pending := models.TwoFactor{
+		UserID:     userID,
+		TOTPSecret: secret,
+	}
+	err := t.Db.QueryRowContext(ctx, query, userID, secret).
+		Scan(&pending.CreatedAt, &pending.UpdatedAt, &pending.Version)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// the conflicting row is enabled, so the update was skipped
+			return models.TwoFactor{}, repository.ErrTwoFactorEnabled
+
+		default:
+			return models.TwoFactor{}, err
+		}
+	}
+
+	return pending, nil
+}
+
+// Enable confirms the pending enrollment of a user and stores their recovery codes.
+// repository.ErrRecordNotFound is returned if there is no pending enrollment.
+func (t twoFactor) Enable(userID string, recoveryCodeHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	enableQuery := `
+	UPDATE public.two_factor
+	SET
+		enabled = TRUE,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE user_id = $2 AND enabled = FALSE;`
+
+	tx, err := t.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	result, err := tx.ExecContext(ctx, enableQuery, time.Now().UTC(), userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
+
+// Disable removes the TOTP enrollment and recovery codes of a user.
+func (t twoFactor) Disable(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	tx, err := t.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM public.recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM public.two_factor WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
+
+// UseStep records the time step of an accepted TOTP code.
+// repository.ErrTokenReused is returned if a code from the same or a later step was already accepted.
+func (t twoFactor) UseStep(userID string, step int64) error {
+	query := `
+	UPDATE public.two_factor
+	SET
+		last_used_step = $1,
+		updated_at = $2
+	WHERE user_id = $3 AND last_used_step < $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := t.Db.ExecContext(ctx, query, step, time.Now().UTC(), userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrTokenReused
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode marks an unused recovery code of a user as used.
+// repository.ErrRecordNotFound is returned if the user has no such unused code.
+func (t twoFactor) ConsumeRecoveryCode(userID string, codeHash string) error {
+	query := `
+	UPDATE public.recovery_codes
+	SET
+		used_at = $1,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := t.Db.ExecContext(ctx, query, time.Now().UTC(), userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes discards the recovery codes of a user in favour of a new set.
+func (t twoFactor) ReplaceRecoveryCodes(userID string, recoveryCodeHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	tx, err := t.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
+
+// GetUnsealedSecrets retrieves up to limit TOTP enrollments whose secrets do not start with sealedPrefix,
+// which were stored before secrets were encrypted.
+func (t twoFactor) GetUnsealedSecrets(sealedPrefix string, limit int) ([]models.TwoFactor, error) {
+	query := `
+	SELECT
+		user_id,
+		totp_secret,
+		enabled,
+		last_used_step,
+		created_at,
+		updated_at,
+		_version
+	FROM public.two_factor
+	WHERE totp_secret NOT LIKE $1 || '%'
+	LIMIT $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := t.Db.QueryContext(ctx, query, likeEscaper.Replace(sealedPrefix), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	enrollments := make([]models.TwoFactor, 0)
+	for rows.Next() {
+		var enrollment models.TwoFactor
+		err := rows.Scan(
+			&enrollment.UserID,
+			&enrollment.TOTPSecret,
+			&enrollment.Enabled,
+			&enrollment.LastUsedStep,
+			&enrollment.CreatedAt,
+			&enrollment.UpdatedAt,
+			&enrollment.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return enrollments, nil
+}
+
+// ReplaceSecret replaces the TOTP secret of a user with the same secret in another form.
+// repository.ErrConcurrentUpdate is returned if the secret is no longer oldSecret, such as after a new enrollment.
+func (t twoFactor) ReplaceSecret(userID string, oldSecret string, newSecret string) error {
+	query := `
+	UPDATE public.two_factor
+	SET
+		totp_secret = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE user_id = $3 AND totp_secret = $4
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := t.Db.ExecContext(ctx, query, newSecret, time.Now().UTC(), userID, oldSecret)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrConcurrentUpdate
+	}
+
+	return nil
+}
+
+// UseChallenge records that the two-factor challenge token with matching id was exchanged for a session.
+// repository.ErrTokenReused is returned if it already was.
+func (t twoFactor) UseChallenge(id string, userID string, expiresAt time.Time) error {
+	query := `
+	INSERT INTO public.two_factor_challenges(id, user_id, expires_at)
+	VALUES($1, $2, $3)
+	ON CONFLICT (id) DO NOTHING
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := t.Db.ExecContext(ctx, query, id, userID, expiresAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrTokenReused
+	}
+
+	return nil
+}
+
+// DeleteExpiredChallenges forgets the used challenge tokens that have expired, which could not be used again anyway.
+func (t twoFactor) DeleteExpiredChallenges(now time.Time) error {
+	query := `DELETE FROM public.two_factor_challenges WHERE expires_at < $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	_, err := t.Db.ExecContext(ctx, query, now)
+	return err
+}
+
+// replaceRecoveryCodes deletes the recovery codes of a user and inserts the new ones within tx.
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID string, recoveryCodeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM public.recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO public.recovery_codes(user_id, code_hash) VALUES($1, $2)`
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, insertQuery, userID, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
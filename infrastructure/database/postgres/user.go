@@ -20,10 +20,15 @@ func NewUserInfrastructure(db *sql.DB) repository.UserRepository {
 	return user{Db: db}
 }
 
+// likeEscaper escapes the wildcards of LIKE patterns, using the default escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // users table constraints
 const (
-	duplicateUsername = "users_username_key"
-	duplicateEmail    = "users_email_key"
+	duplicateUsername   = "users_username_key"
+	duplicateEmail      = "users_email_key"
+	duplicateUsernameCI = "users_username_ci_key"
+	duplicateEmailCI    = "users_email_ci_key"
 )
 
 // Create registers and returns an instance of a new user, it returns an error if a duplicate username or email is used.
@@ -53,7 +58,9 @@ func (u user) Create(user *models.User) (models.User, error) {
 		switch {
 		case
 			strings.Contains(err.Error(), duplicateUsername),
-			strings.Contains(err.Error(), duplicateEmail):
+			strings.Contains(err.Error(), duplicateEmail),
+			strings.Contains(err.Error(), duplicateUsernameCI),
+			strings.Contains(err.Error(), duplicateEmailCI):
 			return models.User{}, repository.ErrDuplicateDetails
 
 		default:
@@ -80,8 +87,11 @@ func (u user) GetById(id string) (models.User, error) {
 		about,
 		follower_count,
 		following_count,
+		private,
 		deleted,
 		is_activated,
+		suspended,
+		purge_after,
 		created_at,
 		updated_at,
 		_version
@@ -106,8 +116,11 @@ func (u user) GetById(id string) (models.User, error) {
 			&foundUser.About,
 			&foundUser.FollowerCount,
 			&foundUser.FollowingCount,
+			&foundUser.Private,
 			&foundUser.Deleted,
 			&foundUser.IsActivated,
+			&foundUser.Suspended,
+			&foundUser.PurgeAfter,
 			&foundUser.CreatedAt,
 			&foundUser.UpdatedAt,
 			&foundUser.Version,
@@ -129,7 +142,7 @@ func (u user) GetById(id string) (models.User, error) {
 	return foundUser, nil
 }
 
-// GetByEmail retrieves an existing user via their email address.
+// GetByEmail retrieves an existing user via their email address, ignoring case.
 // repository.ErrRecordNotFound is returned if no user matches the query.
 func (u user) GetByEmail(email string) (models.User, error) {
 	query := `
@@ -145,13 +158,16 @@ func (u user) GetByEmail(email string) (models.User, error) {
 		about,
 		follower_count,
 		following_count,
+		private,
 		deleted,
 		is_activated,
+		suspended,
+		purge_after,
 		created_at,
 		updated_at,
 		_version
 	FROM public.users
-	WHERE email = $1
+	WHERE lower(email) = lower($1)
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
@@ -171,8 +187,11 @@ func (u user) GetByEmail(email string) (models.User, error) {
 			&foundUser.About,
 			&foundUser.FollowerCount,
 			&foundUser.FollowingCount,
+			&foundUser.Private,
 			&foundUser.Deleted,
 			&foundUser.IsActivated,
+			&foundUser.Suspended,
+			&foundUser.PurgeAfter,
 			&foundUser.CreatedAt,
 			&foundUser.UpdatedAt,
 			&foundUser.Version,
@@ -194,9 +213,10 @@ func (u user) GetByEmail(email string) (models.User, error) {
 	return foundUser, nil
 }
 
-// GetBySearchString retrieves users matching the search string via their first name, last name, username, or email.
-// repository.ErrRecordNotFound is returned if no user matches the query.
-func (u user) GetBySearchString(searchString string) ([]models.User, error) {
+// GetByUsername retrieves an existing user via their username, ignoring case.
+// repository.ErrRecordNotFound is returned if no user matches the query,
+// deleted users are returned along with repository.ErrRecordDeleted.
+func (u user) GetByUsername(username string) (models.User, error) {
 	query := `
 	SELECT 
 		id,
@@ -210,6 +230,84 @@ func (u user) GetBySearchString(searchString string) ([]models.User, error) {
 		about,
 		follower_count,
 		following_count,
+		private,
+		deleted,
+		is_activated,
+		suspended,
+		purge_after,
+		created_at,
+		updated_at,
+		_version
+	FROM public.users
+	WHERE lower(username) = lower($1)
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	foundUser := models.User{}
+	err := u.Db.QueryRowContext(ctx, query, username).
+		Scan(
+			&foundUser.ID,
+			&foundUser.Username,
+			&foundUser.FirstName,
+			&foundUser.LastName,
+			&foundUser.Email,
+			&foundUser.Password,
+			&foundUser.AvatarURL,
+			&foundUser.Status,
+			&foundUser.About,
+			&foundUser.FollowerCount,
+			&foundUser.FollowingCount,
+			&foundUser.Private,
+			&foundUser.Deleted,
+			&foundUser.IsActivated,
+			&foundUser.Suspended,
+			&foundUser.PurgeAfter,
+			&foundUser.CreatedAt,
+			&foundUser.UpdatedAt,
+			&foundUser.Version,
+		)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.User{}, repository.ErrRecordNotFound
+
+		default:
+			return models.User{}, err
+		}
+	}
+
+	if foundUser.Deleted {
+		return foundUser, repository.ErrRecordDeleted
+	}
+
+	return foundUser, nil
+}
+
+// GetBySearchString retrieves users whose username or full name contains the search string, ignoring case.
+// Exact username matches come first, then usernames starting with the search string, then the closest matches.
+// Deleted and suspended users are left out.
+func (u user) GetBySearchString(searchString string, page, pageSize int) ([]models.User, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	offset := (page - 1) * pageSize
+
+	query := `
+	SELECT
+		id,
+		username,
+		first_name,
+		last_name,
+		avatar,
+		status,
+		about,
+		follower_count,
+		following_count,
+		private,
 		deleted,
 		is_activated,
 		created_at,
@@ -217,25 +315,38 @@ func (u user) GetBySearchString(searchString string) ([]models.User, error) {
 		_version
 	FROM public.users
 	WHERE
-		first_name ILIKE $1 OR
-		last_name ILIKE $1 OR
-		username ILIKE $1 OR
-		email ILIKE $1
+		deleted = FALSE AND
+		suspended = FALSE AND
+		(username ILIKE $1 OR (first_name || ' ' || last_name) ILIKE $1)
+	ORDER BY
+		lower(username) = lower($2) DESC,
+		username ILIKE $3 DESC,
+		greatest(similarity(username, $2), similarity(first_name || ' ' || last_name, $2)) DESC,
+		follower_count DESC,
+		id
+	LIMIT $4 OFFSET $5
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
-	// Use a wildcard search for the search string
-	searchPattern := "%" + searchString + "%"
+	// Use a wildcard search for the search string, matching any wildcards in it literally
+	escaped := likeEscaper.Replace(searchString)
+	containsPattern := "%" + escaped + "%"
+	prefixPattern := escaped + "%"
 
-	rows, err := u.Db.QueryContext(ctx, query, searchPattern)
+	rows, err := u.Db.QueryContext(ctx, query, containsPattern, searchString, prefixPattern, pageSize, offset)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
 
-	var users []models.User
+	users := make([]models.User, 0)
 	for rows.Next() {
 		var user models.User
 		err := rows.Scan(
@@ -243,13 +354,12 @@ func (u user) GetBySearchString(searchString string) ([]models.User, error) {
 			&user.Username,
 			&user.FirstName,
 			&user.LastName,
-			&user.Email,
-			&user.Password,
 			&user.AvatarURL,
 			&user.Status,
 			&user.About,
 			&user.FollowerCount,
 			&user.FollowingCount,
+			&user.Private,
 			&user.Deleted,
 			&user.IsActivated,
 			&user.CreatedAt,
@@ -260,15 +370,10 @@ func (u user) GetBySearchString(searchString string) ([]models.User, error) {
 			return nil, err
 		}
 
-		if user.Deleted {
-			return nil, repository.ErrRecordDeleted
-		}
-
 		users = append(users, user)
 	}
-
-	if len(users) == 0 {
-		return nil, repository.ErrRecordNotFound
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return users, nil
@@ -294,10 +399,12 @@ func (u user) GetAll(page, pageSize int) ([]models.User, error) {
 		about,
 		follower_count,
 		following_count,
+		private,
 		deleted,
 		created_at,
 		updated_at
 	FROM public.users
+	WHERE suspended = FALSE
 	LIMIT $1 OFFSET $2
 	`
 
@@ -329,6 +436,7 @@ func (u user) GetAll(page, pageSize int) ([]models.User, error) {
 			&follower.About,
 			&follower.FollowerCount,
 			&follower.FollowingCount,
+			&follower.Private,
 			&follower.Deleted,
 			&follower.CreatedAt,
 			&follower.UpdatedAt,
@@ -369,6 +477,7 @@ func (u user) GetFollowersOfUser(id string, page, pageSize int) ([]models.User,
 		u.about,
 		u.follower_count,
 		u.following_count,
+		u.private,
 		u.deleted,
 		u.created_at,
 		u.updated_at
@@ -406,6 +515,7 @@ func (u user) GetFollowersOfUser(id string, page, pageSize int) ([]models.User,
 			&follower.About,
 			&follower.FollowerCount,
 			&follower.FollowingCount,
+			&follower.Private,
 			&follower.Deleted,
 			&follower.CreatedAt,
 			&follower.UpdatedAt,
@@ -446,6 +556,7 @@ func (u user) GetUsersFollowedBy(id string, page, pageSize int) ([]models.User,
         u.about,
         u.follower_count,
         u.following_count,
+        u.private,
         u.deleted,
         u.created_at,
         u.updated_at
@@ -483,6 +594,7 @@ func (u user) GetUsersFollowedBy(id string, page, pageSize int) ([]models.User,
 			&userFollowed.About,
 			&userFollowed.FollowerCount,
 			&userFollowed.FollowingCount,
+			&userFollowed.Private,
 			&userFollowed.Deleted,
 			&userFollowed.CreatedAt,
 			&userFollowed.UpdatedAt,
@@ -524,9 +636,11 @@ func (u user) Update(id string, updatedUser models.User) (models.User, error) {
 		    status = $6,
 		    about = $7,
 		    avatar = $8,
-		    updated_at = $9,
+		    is_activated = $9,
+		    private = $10,
+		    updated_at = $11,
 		    _version = _version + 1
-		WHERE id = $10 AND _version = $11;`
+		WHERE id = $12 AND _version = $13;`
 
 	tx, err := u.Db.BeginTx(ctx, nil)
 	if err != nil {
@@ -567,6 +681,8 @@ func (u user) Update(id string, updatedUser models.User) (models.User, error) {
 		updatedUser.Status,
 		updatedUser.About,
 		updatedUser.AvatarURL,
+		updatedUser.IsActivated,
+		updatedUser.Private,
 		time.Now().UTC(),
 		id,
 		updatedUser.Version)
@@ -576,7 +692,9 @@ func (u user) Update(id string, updatedUser models.User) (models.User, error) {
 		case errors.Is(err, sql.ErrNoRows):
 			return models.User{}, repository.ErrRecordNotFound
 		case strings.Contains(err.Error(), duplicateUsername),
-			strings.Contains(err.Error(), duplicateEmail):
+			strings.Contains(err.Error(), duplicateEmail),
+			strings.Contains(err.Error(), duplicateUsernameCI),
+			strings.Contains(err.Error(), duplicateEmailCI):
 			return models.User{}, repository.ErrDuplicateDetails
 		default:
 			return models.User{}, err
@@ -596,9 +714,9 @@ func (u user) Update(id string, updatedUser models.User) (models.User, error) {
 	return updatedUser, nil
 }
 
-// Delete sets the deleted field  for instance of user with matching id.
-// repository.ErrRecordNotFound is returned if no user matches the query.
-func (u user) Delete(id string, deletedUser models.User) (models.User, error) {
+// Delete sets the deleted field  for instance of user with matching id and schedules the user to be purged
+// at purgeAfter. repository.ErrRecordNotFound is returned if no user matches the query.
+func (u user) Delete(id string, deletedUser models.User, purgeAfter time.Time) (models.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
 	defer cancel()
 
@@ -608,9 +726,10 @@ func (u user) Delete(id string, deletedUser models.User) (models.User, error) {
 	UPDATE public.users
 	SET
 		deleted = TRUE,
-		updated_at = $1,
+		purge_after = $1,
+		updated_at = $2,
 		_version = _version + 1
-	WHERE id = $2 AND _version = $3;`
+	WHERE id = $3 AND _version = $4;`
 
 	tx, err := u.Db.BeginTx(ctx, nil)
 	if err != nil {
@@ -643,6 +762,7 @@ func (u user) Delete(id string, deletedUser models.User) (models.User, error) {
 	// Set deleted flag to TRUE
 	_, err = tx.ExecContext(ctx,
 		deleteQuery,
+		purgeAfter,
 		time.Now().UTC(),
 		id,
 		deletedUser.Version)
@@ -661,5 +781,286 @@ func (u user) Delete(id string, deletedUser models.User) (models.User, error) {
 		return models.User{}, err
 	}
 
+	deletedUser.Deleted = true
+	deletedUser.PurgeAfter = sql.NullTime{Time: purgeAfter, Valid: true}
 	return deletedUser, nil
 }
+
+// UpdatePasswordHash replaces the password hash of the user with matching id, provided it is still currentHash.
+// repository.ErrConcurrentUpdate is returned if the password has been changed in the meantime.
+func (u user) UpdatePasswordHash(id string, currentHash string, newHash string) error {
+	query := `
+	UPDATE public.users
+	SET
+		password_hash = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE id = $3 AND password_hash = $4
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := u.Db.ExecContext(ctx, query, newHash, time.Now().UTC(), id, currentHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrConcurrentUpdate
+	}
+
+	return nil
+}
+
+// CancelDeletion restores a deleted user whose purge is still pending.
+// repository.ErrRecordNotFound is returned if no user matching the id is awaiting a purge.
+func (u user) CancelDeletion(id string) error {
+	query := `
+	UPDATE public.users
+	SET
+		deleted = FALSE,
+		purge_after = NULL,
+		updated_at = $1,
+		_version = _version + 1
+	WHERE id = $2 AND deleted = TRUE AND purge_after > $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := u.Db.ExecContext(ctx, query, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SchedulePurges schedules the deleted users who have no purge scheduled, who were deleted before purging existed,
+// to be purged at purgeAfter. The number of users scheduled is returned.
+func (u user) SchedulePurges(purgeAfter time.Time) (int64, error) {
+	query := `
+	UPDATE public.users
+	SET
+		purge_after = $1,
+		updated_at = $2,
+		_version = _version + 1
+	WHERE deleted = TRUE AND purge_after IS NULL
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	result, err := u.Db.ExecContext(ctx, query, purgeAfter, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetDueForPurge retrieves the IDs and avatars of up to limit deleted users whose grace period ended before the given time.
+func (u user) GetDueForPurge(before time.Time, limit int) ([]models.User, error) {
+	query := `
+	SELECT
+		id,
+		avatar,
+		purge_after
+	FROM public.users
+	WHERE deleted = TRUE AND purge_after <= $1
+	ORDER BY purge_after
+	LIMIT $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := u.Db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.AvatarURL, &user.PurgeAfter); err != nil {
+			return nil, err
+		}
+		user.Deleted = true
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetMedia retrieves the IDs of the memos and comments with uploaded media that go when the user is purged,
+// those being the user's own and the comments left on the user's memos.
+func (u user) GetMedia(id string) (models.UserMedia, error) {
+	query := `
+	SELECT 'memo', id
+	FROM public.memos
+	WHERE owner_id = $1 AND memo_type <> 'text'
+	UNION ALL
+	SELECT 'comment', id
+	FROM public.comments
+	WHERE comment_type <> 'text'
+		AND (owner_id = $1 OR memo_id IN (SELECT id FROM public.memos WHERE owner_id = $1))
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	rows, err := u.Db.QueryContext(ctx, query, id)
+	if err != nil {
+		return models.UserMedia{}, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	media := models.UserMedia{}
+	for rows.Next() {
+		var kind, mediaID string
+		if err := rows.Scan(&kind, &mediaID); err != nil {
+			return models.UserMedia{}, err
+		}
+		switch kind {
+		case "memo":
+			media.MemoIDs = append(media.MemoIDs, mediaID)
+		default:
+			media.CommentIDs = append(media.CommentIDs, mediaID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return models.UserMedia{}, err
+	}
+
+	return media, nil
+}
+
+// Purge permanently removes a deleted user whose grace period has ended, along with their memos, likes,
+// shares, follows, credentials and data exports, and the comments left on their memos. Comments the user left elsewhere
+// are kept for the sake of their replies but stripped of their content and owner. The counts of the users
+// and memos the user interacted with are corrected, and the username and email become available again.
+// repository.ErrRecordNotFound is returned if the user is not awaiting a purge, such as when it was cancelled.
+func (u user) Purge(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), helpers.TimeoutDuration)
+	defer cancel()
+
+	// Query statements
+	selectQuery := `SELECT id FROM public.users WHERE id = $1 AND deleted = TRUE AND purge_after <= $2 FOR UPDATE;`
+	purgeQueries := []string{
+		// follows
+		`UPDATE public.users SET follower_count = follower_count - 1
+		WHERE id IN (SELECT subject_id FROM public.follow WHERE follower_id = $1);`,
+		`UPDATE public.users SET following_count = following_count - 1
+		WHERE id IN (SELECT follower_id FROM public.follow WHERE subject_id = $1);`,
+		`DELETE FROM public.follow WHERE follower_id = $1 OR subject_id = $1;`,
+
+		// likes and shares
+		`UPDATE public.memos SET likes = likes - 1
+		WHERE id IN (SELECT memo_id FROM public.likes WHERE liked_by = $1);`,
+		`DELETE FROM public.likes
+		WHERE liked_by = $1 OR memo_id IN (SELECT id FROM public.memos WHERE owner_id = $1);`,
+		`UPDATE public.memos SET shares = shares - 1
+		WHERE id IN (SELECT memo_id FROM public.shares WHERE shared_by = $1);`,
+		`DELETE FROM public.shares
+		WHERE shared_by = $1 OR memo_id IN (SELECT id FROM public.memos WHERE owner_id = $1);`,
+
+		// comments and memos
+		`DELETE FROM public.comments
+		WHERE memo_id IN (SELECT id FROM public.memos WHERE owner_id = $1);`,
+		`UPDATE public.comments
+		SET
+			owner_id = NULL,
+			comment_content = '',
+			caption = '',
+			transcript = '',
+			deleted = TRUE,
+			updated_at = now(),
+			_version = _version + 1
+		WHERE owner_id = $1;`,
+		`DELETE FROM public.memos WHERE owner_id = $1;`,
+
+		// credentials, sessions and roles
+		`DELETE FROM public.refresh_tokens WHERE user_id = $1;`,
+		`DELETE FROM public.sessions WHERE user_id = $1;`,
+		`DELETE FROM public.password_reset_tokens WHERE user_id = $1;`,
+		`DELETE FROM public.recovery_codes WHERE user_id = $1;`,
+		`DELETE FROM public.two_factor WHERE user_id = $1;`,
+		`DELETE FROM public.two_factor_challenges WHERE user_id = $1;`,
+		`DELETE FROM public.personal_access_tokens WHERE user_id = $1;`,
+		`DELETE FROM public.user_roles WHERE user_id = $1;`,
+		`DELETE FROM public.follow_requests WHERE follower_id = $1 OR subject_id = $1;`,
+		`DELETE FROM public.blocks WHERE blocker_id = $1 OR blocked_id = $1;`,
+		`DELETE FROM public.mutes WHERE muter_id = $1 OR muted_id = $1;`,
+		`DELETE FROM public.muted_words WHERE user_id = $1;`,
+		`DELETE FROM public.close_friends WHERE user_id = $1 OR friend_id = $1;`,
+		`DELETE FROM public.list_members WHERE user_id = $1;`,
+		`DELETE FROM public.list_followers WHERE user_id = $1;`,
+		`DELETE FROM public.lists WHERE owner_id = $1;`,
+		`DELETE FROM public.data_exports WHERE user_id = $1;`,
+		`DELETE FROM public.login_failures WHERE scope = 'account' AND subject = $1::text;`,
+		`DELETE FROM public.lockouts WHERE scope = 'account' AND subject = $1::text;`,
+
+		`DELETE FROM public.users WHERE id = $1;`,
+	}
+
+	tx, err := u.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil {
+			return
+		}
+	}(tx)
+
+	// Lock the user so that a cancelled deletion cannot race the purge
+	var userID string
+	err = tx.QueryRowContext(ctx, selectQuery, id, time.Now().UTC()).Scan(&userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	for _, query := range purgeQueries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	// Commit the transaction
+	return tx.Commit()
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+)
+
+type logMailer struct {
+	From string
+	Dir  string
+}
+
+// NewLogMailer returns a mailer for development and tests which never delivers anything.
+// Emails are written as .eml files to dir, or to the log when dir is empty.
+func NewLogMailer(from, dir string) repository.Mailer {
+	return logMailer{
+		From: from,
+		Dir:  dir,
+	}
+}
+
+// Send writes the email to the mail directory or the log.
+func (l logMailer) Send(email models.Email) error {
+	message := compose(l.From, email)
+
+	if l.Dir == "" {
+		log.Printf("email to %s:\n%s\n", email.To, message)
+		return nil
+	}
+
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), filepath.Base(email.To))
+	return os.WriteFile(filepath.Join(l.Dir, name), message, 0o644)
+}
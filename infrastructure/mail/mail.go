@@ -0,0 +1,25 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+)
+
+// compose renders an email as an RFC 5322 message with a plain text body.
+func compose(from string, email models.Email) []byte {
+	var message strings.Builder
+
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", email.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", email.Subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+
+	return []byte(message.String())
+}
@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"fmt"
+	"net/mail"
+	"net/smtp"
+
+	"github.com/akinolaemmanuel49/memo-api/domain/models"
+	"github.com/akinolaemmanuel49/memo-api/domain/repository"
+)
+
+type smtpMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) repository.Mailer {
+	return smtpMailer{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+// Send delivers the email through the configured SMTP server.
+// Authentication is only attempted when a username is set.
+func (s smtpMailer) Send(email models.Email) error {
+	sender, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	return smtp.SendMail(
+		fmt.Sprintf("%s:%d", s.Host, s.Port),
+		auth,
+		sender.Address,
+		[]string{email.To},
+		compose(s.From, email),
+	)
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a concurrency safe, in-memory key-value store whose entries expire after a fixed duration.
+type Cache[K comparable, V any] struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	items     map[K]item[V]
+	lastSweep time.Time
+}
+
+type item[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// New returns an empty cache whose entries live for ttl.
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:       ttl,
+		items:     make(map[K]item[V]),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the value stored under key, and false if there is none or it has expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.items[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return *new(V), false
+	}
+	return entry.value, true
+}
+
+// Set stores value under key, replacing any existing entry.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.items[key] = item[V]{value: value, expiresAt: now.Add(c.ttl)}
+
+	// expired entries are only removed once per ttl to keep writes cheap
+	if now.Sub(c.lastSweep) > c.ttl {
+		for k, entry := range c.items {
+			if now.After(entry.expiresAt) {
+				delete(c.items, k)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// Delete removes the entry stored under key.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+}
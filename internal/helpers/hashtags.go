@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxHashtagLength is the number of characters hashtags are cut down to.
+const MaxHashtagLength = 100
+
+// ExtractHashtags returns the distinct hashtags in text, normalized by NormalizeHashtag, in order of appearance.
+// A hashtag is a # followed by letters, marks, digits and underscores, not all of them digits. A # directly following
+// a letter, digit or underscore or inside a link does not start a hashtag, so that links such as example.com/page#part
+// and https://example.com/#part are left out.
+func ExtractHashtags(text string) []string {
+	runes := []rune(text)
+	seen := make(map[string]bool)
+	hashtags := make([]string, 0)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' || (i > 0 && isHashtagRune(runes[i-1])) || inLink(runes, i) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isHashtagRune(runes[end]) {
+			end++
+		}
+
+		hashtag := NormalizeHashtag(string(runes[i+1 : end]))
+		if hashtag != "" && strings.IndexFunc(hashtag, isNotDigit) >= 0 && !seen[hashtag] {
+			seen[hashtag] = true
+			hashtags = append(hashtags, hashtag)
+		}
+		i = end - 1
+	}
+
+	return hashtags
+}
+
+// NormalizeHashtag lowercases a hashtag, with or without its leading #, and cuts it down to MaxHashtagLength characters.
+// An empty string is returned if the hashtag contains anything but letters, marks, digits and underscores.
+func NormalizeHashtag(hashtag string) string {
+	runes := []rune(strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hashtag), "#")))
+	for _, r := range runes {
+		if !isHashtagRune(r) {
+			return ""
+		}
+	}
+	if len(runes) > MaxHashtagLength {
+		runes = runes[:MaxHashtagLength]
+	}
+
+	return string(runes)
+}
+
+// isHashtagRune reports whether r may be part of a hashtag.
+// Marks are allowed for the scripts that combine them with letters, such as Devanagari.
+func isHashtagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r)
+}
+
+// inLink reports whether the rune at i belongs to a word containing a URL scheme, such as https://.
+func inLink(runes []rune, i int) bool {
+	start := i
+	for start > 0 && !unicode.IsSpace(runes[start-1]) {
+		start--
+	}
+	return strings.Contains(string(runes[start:i]), "://")
+}
+
+func isNotDigit(r rune) bool {
+	return !unicode.IsDigit(r)
+}
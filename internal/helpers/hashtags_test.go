@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"no hashtags", "just a memo", []string{}},
+		{"single hashtag", "hello #world", []string{"world"}},
+		{"order of appearance", "#b then #a then #c", []string{"b", "a", "c"}},
+		{"lowercased", "#GoLang", []string{"golang"}},
+		{"underscores and digits", "#go_1_22 #2024goals", []string{"go_1_22", "2024goals"}},
+		{"digits only", "issue #123", []string{}},
+		{"trailing punctuation", "#go, #rust! (#zig). #c?", []string{"go", "rust", "zig", "c"}},
+		{"lone hash", "# and ##", []string{}},
+		{"repeated hash", "##go", []string{"go"}},
+
+		{"duplicates", "#go #rust #go", []string{"go", "rust"}},
+		{"duplicates in other cases", "#Go #GO #go", []string{"go"}},
+
+		{"accented letters", "#café #Über", []string{"café", "über"}},
+		{"non latin scripts", "#日本語 #привет #مرحبا", []string{"日本語", "привет", "مرحبا"}},
+		{"combining marks", "#हिन्दी #cafe\u0301", []string{"हिन्दी", "cafe\u0301"}},
+		{"unicode digits only", "#١٢٣", []string{}},
+		{"emoji ends a hashtag", "#go🚀", []string{"go"}},
+
+		{"hash inside a word", "c#sharp and abc#def", []string{}},
+		{"hash after an underscore", "snake_#case", []string{}},
+		{"fragment after a path", "see example.com/page#part", []string{}},
+		{"fragment after a slash", "see https://example.com/#part", []string{}},
+		{"fragment in a query", "https://example.com/search?q=1&tag=#part", []string{}},
+		{"hashtag after a link", "https://example.com/page #news", []string{"news"}},
+		{"hashtag before a link", "#news https://example.com/#part", []string{"news"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractHashtags(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractHashtags(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractHashtagsCutsLongHashtags(t *testing.T) {
+	long := strings.Repeat("é", MaxHashtagLength+10)
+
+	got := ExtractHashtags("#" + long)
+	if want := []string{strings.Repeat("é", MaxHashtagLength)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want the hashtag cut down to %d characters", got, MaxHashtagLength)
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	tests := []struct {
+		hashtag string
+		want    string
+	}{
+		{"go", "go"},
+		{"#Go", "go"},
+		{"  #Go  ", "go"},
+		{"Über", "über"},
+		{"go lang", ""},
+		{"go-lang", ""},
+		{"#", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeHashtag(tt.hashtag); got != tt.want {
+			t.Errorf("NormalizeHashtag(%q) = %q, want %q", tt.hashtag, got, tt.want)
+		}
+	}
+}